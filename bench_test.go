@@ -0,0 +1,84 @@
+package simpleenv
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+const benchRegexPattern = `^(http|https)://[a-z0-9.-]+(:[0-9]+)?(/.*)?$`
+
+func BenchmarkMatchRegex(b *testing.B) {
+	b.Run("uncached", func(b *testing.B) {
+		for b.Loop() {
+			re := regexp.MustCompile(benchRegexPattern)
+			re.MatchString("https://api.example.com:8443/v1")
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		for b.Loop() {
+			_, _ = matchRegex(benchRegexPattern, "https://api.example.com:8443/v1")
+		}
+	})
+}
+
+func BenchmarkLoadRegexConstraint(b *testing.B) {
+	type cfg struct {
+		URL string `env:"SIMPLEENV_BENCH_URL;regex='^(http|https)://[a-z0-9.-]+(:[0-9]+)?(/.*)?$'"`
+	}
+
+	source := MapSource{"SIMPLEENV_BENCH_URL": "https://api.example.com:8443/v1"}
+	for b.Loop() {
+		var c cfg
+		if err := LoadWithOptions(&c, WithSource(source)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type benchConfig struct {
+	Name    string        `env:"SIMPLEENV_BENCH_NAME;minlen=1"`
+	Port    int           `env:"SIMPLEENV_BENCH_PORT;min=1;max=65535"`
+	Timeout time.Duration `env:"SIMPLEENV_BENCH_TIMEOUT;default=5s"`
+	Mode    string        `env:"SIMPLEENV_BENCH_MODE;oneof=dev,prod"`
+	Hosts   []string      `env:"SIMPLEENV_BENCH_HOSTS;optional"`
+	DB      struct {
+		Host string `env:"SIMPLEENV_BENCH_DB_HOST"`
+		Port int    `env:"SIMPLEENV_BENCH_DB_PORT;min=1"`
+	}
+}
+
+var benchSource = MapSource{
+	"SIMPLEENV_BENCH_NAME":    "api",
+	"SIMPLEENV_BENCH_PORT":    "8080",
+	"SIMPLEENV_BENCH_MODE":    "prod",
+	"SIMPLEENV_BENCH_HOSTS":   "a,b,c",
+	"SIMPLEENV_BENCH_DB_HOST": "db.local",
+	"SIMPLEENV_BENCH_DB_PORT": "5432",
+}
+
+func BenchmarkLoad(b *testing.B) {
+	b.Run("LoadWithOptions", func(b *testing.B) {
+		for b.Loop() {
+			var c benchConfig
+			if err := LoadWithOptions(&c, WithSource(benchSource)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Compile+Load", func(b *testing.B) {
+		schema, err := Compile[benchConfig](WithSource(benchSource))
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for b.Loop() {
+			var c benchConfig
+			if err := schema.Load(&c); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
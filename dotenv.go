@@ -0,0 +1,247 @@
+package simpleenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadFile works like LoadWithOptions, but also reads KEY=VALUE lines from
+// the .env file at path. Values already set in the configured Source (the
+// process environment by default) take precedence over file values; use
+// WithFileOverride to let the file win instead.
+//
+//	err := simpleenv.LoadFile(&cfg, ".env")
+//
+// The file format supports # comments, an optional "export " prefix, and
+// single- or double-quoted values. Double-quoted values may span lines and
+// support the \n, \r, \t, \", \\, and \$ escapes; single-quoted values are
+// taken literally. Values are not expanded unless WithExpandVars is used, and
+// an escaped \$ stays a literal $ even then.
+func LoadFile(envConfig any, path string, opts ...Option) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read env file %q: %w", path, err)
+	}
+
+	l := newLoader(opts)
+	fileValues, err := parseDotenv(string(data), l.expandVars)
+	if err != nil {
+		return fmt.Errorf("failed to parse env file %q: %w", path, err)
+	}
+
+	layers := []Source{l.source, MapSource(fileValues)}
+	if l.fileOverride {
+		layers = []Source{MapSource(fileValues), l.source}
+	}
+	l.source = layeredSource{layers: layers, skipEmpty: l.emptyAsUnset}
+
+	return l.load(envConfig)
+}
+
+// Unmarshal parses data as .env content, in the format LoadFile reads, and
+// loads envConfig from it alone: the process environment and any Source
+// given with WithSource are not consulted. It suits config delivered as a
+// blob, such as an embedded file or a secret fetched over the network.
+//
+//	//go:embed defaults.env
+//	var defaults []byte
+//
+//	err := simpleenv.Unmarshal(defaults, &cfg)
+func Unmarshal(data []byte, envConfig any, opts ...Option) error {
+	l := newLoader(opts)
+	values, err := parseDotenv(string(data), l.expandVars)
+	if err != nil {
+		return fmt.Errorf("failed to parse env data: %w", err)
+	}
+
+	l.source = MapSource(values)
+
+	return l.load(envConfig)
+}
+
+// WithFileOverride makes values from the file passed to LoadFile take
+// precedence over the configured Source.
+func WithFileOverride() Option {
+	return func(o *options) {
+		o.fileOverride = true
+	}
+}
+
+// layeredSource looks a key up in each Source in order and returns the
+// first value found. With skipEmpty (set by WithEmptyAsUnset), an empty value
+// does not count as found, so a later layer can still provide the key.
+type layeredSource struct {
+	layers    []Source
+	skipEmpty bool
+}
+
+func (s layeredSource) Lookup(key string) (string, bool) {
+	for _, source := range s.layers {
+		if value, ok := source.Lookup(key); ok && (value != "" || !s.skipEmpty) {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// LookupContext looks key up like Lookup, using LookupContext for layers
+// that implement ContextSource.
+func (s layeredSource) LookupContext(ctx context.Context, key string) (string, bool, error) {
+	for _, source := range s.layers {
+		value, ok, err := lookupContext(ctx, source, key)
+		if err != nil || (ok && (value != "" || !s.skipEmpty)) {
+			return value, ok, err
+		}
+	}
+
+	return "", false, nil
+}
+
+// Keys returns the keys of every layer that implements KeySource.
+func (s layeredSource) Keys() []string {
+	var keys []string
+	for _, source := range s.layers {
+		if keySource, ok := source.(KeySource); ok {
+			keys = append(keys, keySource.Keys()...)
+		}
+	}
+
+	return keys
+}
+
+// parseDotenv parses .env file contents into a map. Later assignments to the
+// same key win. When expandVars is set, an escaped \$ and every $ in a
+// single-quoted value are kept as $$ so that expandValue leaves them literal.
+func parseDotenv(data string, expandVars bool) (map[string]string, error) {
+	values := map[string]string{}
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		key, rawValue, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE, got %q", lineNumber, line)
+		}
+
+		key = strings.TrimSpace(key)
+		if !isDotenvKey(key) {
+			return nil, fmt.Errorf("line %d: invalid key %q", lineNumber, key)
+		}
+
+		rawValue = strings.TrimLeft(rawValue, " \t")
+		if rawValue == "" || (rawValue[0] != '"' && rawValue[0] != '\'') {
+			values[key] = stripInlineComment(rawValue)
+			continue
+		}
+
+		quote := rawValue[0]
+		rawValue = rawValue[1:]
+		var value strings.Builder
+		for {
+			rest, closed := readQuoted(&value, rawValue, quote, expandVars)
+			if closed {
+				rest = strings.TrimSpace(rest)
+				if rest != "" && !strings.HasPrefix(rest, "#") {
+					return nil, fmt.Errorf("line %d: unexpected characters after closing quote in %q", lineNumber, key)
+				}
+
+				break
+			}
+
+			i++
+			if i == len(lines) {
+				return nil, fmt.Errorf("line %d: unterminated quoted value for %q", lineNumber, key)
+			}
+
+			value.WriteByte('\n')
+			rawValue = lines[i]
+		}
+
+		values[key] = value.String()
+	}
+
+	return values, nil
+}
+
+// readQuoted appends the quoted text in s up to the closing quote to value,
+// and returns what follows the closing quote. closed is false when s ends
+// before the quote is closed. escapeDollars writes \$ as $$ instead of $, and
+// every $ of a single-quoted value as $$, since both are literal.
+func readQuoted(value *strings.Builder, s string, quote byte, escapeDollars bool) (rest string, closed bool) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == quote {
+			return s[i+1:], true
+		}
+
+		if quote == '"' && c == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case '$':
+				if escapeDollars {
+					value.WriteByte('$')
+				}
+				value.WriteByte('$')
+			case '"', '\\':
+				value.WriteByte(s[i])
+			default:
+				value.WriteByte('\\')
+				value.WriteByte(s[i])
+			}
+
+			continue
+		}
+
+		if quote == '\'' && c == '$' && escapeDollars {
+			value.WriteByte('$')
+		}
+		value.WriteByte(c)
+	}
+
+	return "", false
+}
+
+// stripInlineComment removes a trailing " # comment" from an unquoted value.
+// The value has already been trimmed on the left, so a value that starts with
+// # (as in KEY= # comment) is a comment and the value is empty.
+func stripInlineComment(value string) string {
+	if strings.HasPrefix(value, "#") {
+		return ""
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+
+	return strings.TrimSpace(value)
+}
+
+func isDotenvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for i, r := range key {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && (i == 0 || (!isDigit && r != '.')) {
+			return false
+		}
+	}
+
+	return true
+}
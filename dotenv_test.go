@@ -0,0 +1,291 @@
+package simpleenv
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeEnvFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write env file: %v", err)
+	}
+
+	return path
+}
+
+func TestParseDotenv(t *testing.T) {
+	content := `# comment
+SIMPLE=value
+export EXPORTED=yes
+  SPACED = padded value  
+INLINE=value # trailing comment
+HASH=abc#def
+EMPTY=
+EMPTY_COMMENT= # nothing here
+SINGLE='literal $HOME \n # not a comment'
+DOUBLE="line1\nline2 \"quoted\" \$HOME \\ end" # comment
+MULTI="first
+second"
+windows=crlf` + "\r\n" + `DOTTED.KEY=1
+`
+
+	got, err := parseDotenv(content, false)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := map[string]string{
+		"SIMPLE":        "value",
+		"EXPORTED":      "yes",
+		"SPACED":        "padded value",
+		"INLINE":        "value",
+		"HASH":          "abc#def",
+		"EMPTY":         "",
+		"EMPTY_COMMENT": "",
+		"SINGLE":        `literal $HOME \n # not a comment`,
+		"DOUBLE":        "line1\nline2 \"quoted\" $HOME \\ end",
+		"MULTI":         "first\nsecond",
+		"windows":       "crlf",
+		"DOTTED.KEY":    "1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected values:\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestParseDotenvErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		contains string
+	}{
+		{name: "missing equals", content: "JUSTAKEY", contains: "line 1: expected KEY=VALUE"},
+		{name: "invalid key", content: "\n1BAD=value", contains: `line 2: invalid key "1BAD"`},
+		{name: "unterminated quote", content: "KEY=\"open\nstill open", contains: "line 1: unterminated quoted value"},
+		{name: "text after quote", content: "KEY='a' b", contains: "unexpected characters after closing quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseDotenv(tt.content, false)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Fatalf("expected error to contain %q, got %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	type cfg struct {
+		Name string `env:"SIMPLEENV_TEST_FILE_NAME"`
+		Port int    `env:"SIMPLEENV_TEST_FILE_PORT;min=1"`
+	}
+
+	path := writeEnvFile(t, "SIMPLEENV_TEST_FILE_NAME=from-file\nSIMPLEENV_TEST_FILE_PORT=8080\n")
+
+	t.Run("file values fill unset keys", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_FILE_NAME")
+		unsetEnv(t, "SIMPLEENV_TEST_FILE_PORT")
+
+		var c cfg
+		if err := LoadFile(&c, path); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Name != "from-file" || c.Port != 8080 {
+			t.Fatalf("unexpected config: %+v", c)
+		}
+		if _, ok := os.LookupEnv("SIMPLEENV_TEST_FILE_NAME"); ok {
+			t.Fatal("expected LoadFile not to modify the process environment")
+		}
+	})
+
+	t.Run("process environment wins by default", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_FILE_NAME", "from-env")
+		unsetEnv(t, "SIMPLEENV_TEST_FILE_PORT")
+
+		var c cfg
+		if err := LoadFile(&c, path); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Name != "from-env" {
+			t.Fatalf("expected env value to win, got %q", c.Name)
+		}
+	})
+
+	t.Run("WithFileOverride lets the file win", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_FILE_NAME", "from-env")
+
+		var c cfg
+		if err := LoadFile(&c, path, WithFileOverride()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Name != "from-file" {
+			t.Fatalf("expected file value to win, got %q", c.Name)
+		}
+	})
+
+	t.Run("WithEmptyAsUnset falls through empty layers", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_FILE_NAME")
+		t.Setenv("SIMPLEENV_TEST_FILE_PORT", "")
+
+		var c cfg
+		if err := LoadFile(&c, path, WithEmptyAsUnset()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Port != 8080 {
+			t.Fatalf("expected the file to fill the empty env value, got %d", c.Port)
+		}
+
+		t.Setenv("SIMPLEENV_TEST_FILE_PORT", "9090")
+		emptyFile := writeEnvFile(t, "SIMPLEENV_TEST_FILE_NAME=\nSIMPLEENV_TEST_FILE_PORT=\n")
+		t.Setenv("SIMPLEENV_TEST_FILE_NAME", "from-env")
+		if err := LoadFile(&c, emptyFile, WithEmptyAsUnset(), WithFileOverride()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Name != "from-env" || c.Port != 9090 {
+			t.Fatalf("expected the env to fill empty file values, got %+v", c)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		var c cfg
+		err := LoadFile(&c, filepath.Join(t.TempDir(), "missing.env"))
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("expected errors.Is(err, fs.ErrNotExist), got %v", err)
+		}
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		var c cfg
+		err := LoadFile(&c, writeEnvFile(t, "NOT A LINE"))
+		if err == nil || !strings.Contains(err.Error(), "failed to parse env file") {
+			t.Fatalf("expected parse error, got %v", err)
+		}
+	})
+}
+
+func TestLoadFileReadsMarshalOutput(t *testing.T) {
+	c := newMarshalConfig()
+	c.Token = strPtr("a b\nc\\d")
+
+	out, err := Marshal(&c)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var loaded marshalConfig
+	err = LoadFile(&loaded, writeEnvFile(t, out), WithSource(MapSource{}))
+	if err != nil {
+		t.Fatalf("expected marshaled output to load, got %v\n%s", err, out)
+	}
+
+	c.internal = ""
+	if !reflect.DeepEqual(loaded, c) {
+		t.Fatalf("round trip mismatch:\ngot:  %+v\nwant: %+v", loaded, c)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	type cfg struct {
+		Host string `env:"SIMPLEENV_TEST_UNMARSHAL_HOST"`
+		Port int    `env:"SIMPLEENV_TEST_UNMARSHAL_PORT;min=1"`
+		URL  string `env:"SIMPLEENV_TEST_UNMARSHAL_URL;optional"`
+	}
+
+	t.Run("loads dotenv content", func(t *testing.T) {
+		var c cfg
+		err := Unmarshal([]byte("# config\nSIMPLEENV_TEST_UNMARSHAL_HOST=\"db.local\"\nexport SIMPLEENV_TEST_UNMARSHAL_PORT=5432\n"), &c)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Host != "db.local" || c.Port != 5432 {
+			t.Fatalf("unexpected config: %+v", c)
+		}
+	})
+
+	t.Run("ignores the process environment", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_UNMARSHAL_PORT", "8080")
+
+		var c cfg
+		err := Unmarshal([]byte("SIMPLEENV_TEST_UNMARSHAL_HOST=db.local"), &c)
+		if !errors.Is(err, ErrMissingRequired) {
+			t.Fatalf("expected missing required error, got %v", err)
+		}
+	})
+
+	t.Run("applies options", func(t *testing.T) {
+		var c cfg
+		data := "SIMPLEENV_TEST_UNMARSHAL_HOST=db.local\nSIMPLEENV_TEST_UNMARSHAL_PORT=5432\nSIMPLEENV_TEST_UNMARSHAL_URL=postgres://${SIMPLEENV_TEST_UNMARSHAL_HOST}\n"
+		err := Unmarshal([]byte(data), &c, WithExpandVars())
+		if err != nil || c.URL != "postgres://db.local" {
+			t.Fatalf("expected expanded URL, got %+v (%v)", c, err)
+		}
+	})
+
+	t.Run("escaped dollars stay literal with expansion", func(t *testing.T) {
+		type secrets struct {
+			Password string `env:"PASSWORD"`
+			Price    string `env:"PRICE"`
+		}
+
+		out, err := Marshal(&secrets{Password: "p$ss", Price: "cost $HOME"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		var c secrets
+		err = Unmarshal([]byte(out), &c, WithStrictExpandVars())
+		if err != nil {
+			t.Fatalf("expected no error, got %v\n%s", err, out)
+		}
+		if c.Password != "p$ss" || c.Price != "cost $HOME" {
+			t.Fatalf("expected round trip to keep dollars, got %+v\n%s", c, out)
+		}
+
+		err = Unmarshal([]byte(`PRICE="cost \$HOME, or $$5"`+"\nPASSWORD=x\n"), &c, WithExpandVars())
+		if err != nil || c.Price != "cost $HOME, or $5" {
+			t.Fatalf("expected escaped dollar to stay literal, got %q (%v)", c.Price, err)
+		}
+	})
+
+	t.Run("escaped dollars stay literal through references", func(t *testing.T) {
+		type refCfg struct {
+			A    string `env:"A"`
+			B    string `env:"B"`
+			Mode string `env:"MODE;oneof=$MODES"`
+			Home string `env:"HOME_DIR"`
+		}
+
+		data := `A="x\$y"` + "\nB=${A}-z\n" + `MODES="a\$b,c"` + "\nMODE=a$$b\n" + "HOME_DIR='$HOME'\n"
+		var c refCfg
+		err := Unmarshal([]byte(data), &c, WithStrictExpandVars())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := refCfg{A: "x$y", B: "x$y-z", Mode: "a$b", Home: "$HOME"}
+		if c != want {
+			t.Fatalf("unexpected config:\ngot:  %+v\nwant: %+v", c, want)
+		}
+	})
+
+	t.Run("reports invalid content and values", func(t *testing.T) {
+		err := Unmarshal([]byte("NOT A LINE"), &cfg{})
+		if err == nil || !strings.Contains(err.Error(), "failed to parse env data: line 1") {
+			t.Fatalf("expected parse error, got %v", err)
+		}
+
+		err = Unmarshal([]byte("SIMPLEENV_TEST_UNMARSHAL_HOST=h\nSIMPLEENV_TEST_UNMARSHAL_PORT=0"), &cfg{})
+		if !errors.Is(err, ErrConstraint) {
+			t.Fatalf("expected constraint error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package simpleenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Sentinel errors wrapped by every error Load returns, so callers can branch
+// on the kind of failure with errors.Is:
+//
+//	if errors.Is(err, simpleenv.ErrMissingRequired) {
+//		log.Fatal("missing configuration, see .env.example")
+//	}
+var (
+	// ErrInvalidInput is returned when Load is not given a non-nil pointer to a struct.
+	ErrInvalidInput = errors.New("invalid Load input")
+	// ErrInvalidTag is returned when a struct tag is malformed or uses an unsupported option.
+	ErrInvalidTag = errors.New("invalid tag")
+	// ErrMissingRequired is returned when a required env var is not set.
+	ErrMissingRequired = errors.New("missing required value")
+	// ErrParse is returned when a value cannot be parsed into the field type.
+	ErrParse = errors.New("parse error")
+	// ErrConstraint is returned when a value does not satisfy a tag constraint.
+	ErrConstraint = errors.New("constraint violation")
+	// ErrUnsupportedType is returned when a field has a type Load cannot populate.
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrUnknownKey is returned when WithExpectedPrefix finds a key no field reads.
+	ErrUnknownKey = errors.New("unknown key")
+	// ErrDeprecatedKey marks the warning reported when a field tagged with
+	// deprecated= is read from the environment. It is never returned by Load.
+	ErrDeprecatedKey = errors.New("deprecated key")
+)
+
+// kindError carries a human-readable message and wraps one of the sentinel
+// errors without repeating it in the message.
+type kindError struct {
+	kind error
+	msg  string
+}
+
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+func newKindError(kind error, format string, args ...any) error {
+	return &kindError{kind: kind, msg: fmt.Sprintf(format, args...)}
+}
+
+// FieldError describes a field whose value is missing, cannot be parsed, or
+// does not satisfy a constraint. Use errors.As to inspect it:
+//
+//	var fieldErr *simpleenv.FieldError
+//	if errors.As(err, &fieldErr) {
+//		metrics.Inc("config_error", fieldErr.EnvKey)
+//	}
+//
+// FieldError unwraps to its Kind (ErrMissingRequired, ErrParse, or
+// ErrConstraint) and to Err, if set.
+type FieldError struct {
+	// Field is the Go field path, e.g. "DB.Port".
+	Field string
+	// EnvKey is the env key the value was read from.
+	EnvKey string
+	// Constraint is the tag option that failed (e.g. "min=1"). It is "required"
+	// for missing values and empty for parse errors.
+	Constraint string
+	// Value is the offending raw value, or "<unset>" when missing. It is
+	// masked for fields tagged with secret.
+	Value string
+	// Expected describes the expected value, e.g. "a value >= 1".
+	Expected string
+	// Kind is the sentinel error describing the failure.
+	Kind error
+	// Err is the underlying cause, if any (e.g. an UnmarshalText error). For
+	// fields tagged with secret its message is left out of Error, since it
+	// may quote the raw value, but it still unwraps.
+	Err error
+}
+
+// maxErrorValueLen is the number of characters of Value shown in Error.
+const maxErrorValueLen = 64
+
+func (e *FieldError) Error() string {
+	got := fmt.Sprintf("%q", e.Value)
+	if runes := []rune(e.Value); len(runes) > maxErrorValueLen {
+		got = fmt.Sprintf("%q (truncated, %d characters)", string(runes[:maxErrorValueLen]), len(runes))
+	}
+
+	msg := fmt.Sprintf("invalid value for field %q from ENV[%q]: got %s, expected %s", e.Field, e.EnvKey, got, e.Expected)
+	if e.Err != nil && !isRedactedError(e.Err) {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+func (e *FieldError) Unwrap() []error {
+	errs := make([]error, 0, 2)
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+
+	return errs
+}
+
+// formattedError replaces the message of err with one rendered by the
+// formatter set with WithErrorFormatter.
+type formattedError struct {
+	err error
+	msg string
+}
+
+func (e *formattedError) Error() string {
+	return e.msg
+}
+
+func (e *formattedError) Unwrap() error {
+	return e.err
+}
+
+// formatError renders err with the WithErrorFormatter formatter when err is a
+// field error or joins only field errors; other errors are returned as is.
+func (l *loader) formatError(err error) error {
+	if err == nil || l.errorFormatter == nil {
+		return err
+	}
+
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok && !isFieldError(err) {
+		errs = joined.Unwrap()
+	}
+
+	fieldErrs := make([]*FieldError, 0, len(errs))
+	for _, e := range errs {
+		var fieldErr *FieldError
+		if !errors.As(e, &fieldErr) {
+			return err
+		}
+
+		fieldErrs = append(fieldErrs, fieldErr)
+	}
+
+	return &formattedError{err: err, msg: l.errorFormatter(fieldErrs)}
+}
+
+func isFieldError(err error) bool {
+	_, ok := err.(*FieldError)
+	return ok
+}
+
+func fieldConstraintError(fieldName, envKey, envValue, constraint, expected string) *FieldError {
+	return &FieldError{Field: fieldName, EnvKey: envKey, Constraint: constraint, Value: envValue, Expected: expected, Kind: ErrConstraint}
+}
+
+func fieldParseError(fieldName, envKey, envValue, expected string) *FieldError {
+	return &FieldError{Field: fieldName, EnvKey: envKey, Value: envValue, Expected: expected, Kind: ErrParse}
+}
+
+func fieldMissingError(fieldName, envKey string) *FieldError {
+	return &FieldError{Field: fieldName, EnvKey: envKey, Constraint: "required", Value: "<unset>", Expected: "a value to set or to be marked as optional", Kind: ErrMissingRequired}
+}
+
+func unsupportedTypeError(fieldName, envKey string, fieldType reflect.Type) error {
+	return newKindError(ErrUnsupportedType, "unsupported type for field %q (ENV[%q]): %v", fieldName, envKey, fieldType)
+}
+
+// tagError reports an invalid struct tag. envKey may be empty when the tag
+// is too malformed to have one.
+func tagError(fieldName, envKey, format string, args ...any) error {
+	detail := fmt.Sprintf(format, args...)
+	if envKey == "" {
+		return newKindError(ErrInvalidTag, "invalid tag for field %q: %s", fieldName, detail)
+	}
+
+	return newKindError(ErrInvalidTag, "invalid tag for field %q (ENV[%q]): %s", fieldName, envKey, detail)
+}
+
+func loadInputError(expected string) error {
+	return newKindError(ErrInvalidInput, "invalid Load input: expected %s", expected)
+}
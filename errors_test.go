@@ -0,0 +1,209 @@
+package simpleenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType reflect.Type
+		tag       string
+		envValue  *string
+		want      error
+	}{
+		{name: "missing required", fieldType: reflect.TypeOf(""), tag: "SIMPLEENV_TEST_SENTINEL_MISSING", envValue: nil, want: ErrMissingRequired},
+		{name: "parse failure", fieldType: reflect.TypeOf(int(0)), tag: "SIMPLEENV_TEST_SENTINEL_PARSE", envValue: strPtr("abc"), want: ErrParse},
+		{name: "constraint failure", fieldType: reflect.TypeOf(int(0)), tag: "SIMPLEENV_TEST_SENTINEL_CONSTRAINT;min=10", envValue: strPtr("1"), want: ErrConstraint},
+		{name: "empty value", fieldType: reflect.TypeOf(""), tag: "SIMPLEENV_TEST_SENTINEL_EMPTY", envValue: strPtr(""), want: ErrConstraint},
+		{name: "unsupported type", fieldType: reflect.TypeOf(map[string]string{}), tag: "SIMPLEENV_TEST_SENTINEL_TYPE", envValue: strPtr("a"), want: ErrUnsupportedType},
+		{name: "invalid tag", fieldType: reflect.TypeOf(""), tag: "SIMPLEENV_TEST_SENTINEL_TAG;nope", envValue: strPtr("a"), want: ErrInvalidTag},
+		{name: "regex mismatch", fieldType: reflect.TypeOf(""), tag: "SIMPLEENV_TEST_SENTINEL_REGEX;regex=^v[0-9]+$", envValue: strPtr("x1"), want: ErrConstraint},
+		{name: "invalid regex pattern", fieldType: reflect.TypeOf(""), tag: "SIMPLEENV_TEST_SENTINEL_REGEX;regex=(", envValue: strPtr("x1"), want: ErrInvalidTag},
+		{name: "text unmarshaler failure", fieldType: reflect.TypeOf(logLevel("")), tag: "SIMPLEENV_TEST_SENTINEL_UNMARSHAL", envValue: strPtr("loud"), want: ErrParse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadSingleField(t, tt.fieldType, tt.tag, tt.envValue)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected errors.Is(err, %v), got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoadInputErrorSentinel(t *testing.T) {
+	err := Load(nil)
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected errors.Is(err, ErrInvalidInput), got %v", err)
+	}
+	if err.Error() != "invalid Load input: expected a non-nil pointer to a struct" {
+		t.Fatalf("unexpected error message: %q", err.Error())
+	}
+}
+
+func TestLoadFieldError(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType reflect.Type
+		tag       string
+		envValue  *string
+		want      FieldError
+	}{
+		{
+			name:      "constraint",
+			fieldType: reflect.TypeOf(int(0)),
+			tag:       "SIMPLEENV_TEST_FIELD_ERROR_MIN;min=10",
+			envValue:  strPtr("1"),
+			want:      FieldError{Field: "Value", EnvKey: "SIMPLEENV_TEST_FIELD_ERROR_MIN", Constraint: "min=10", Value: "1", Expected: "a value >= 10", Kind: ErrConstraint},
+		},
+		{
+			name:      "missing",
+			fieldType: reflect.TypeOf(""),
+			tag:       "SIMPLEENV_TEST_FIELD_ERROR_MISSING",
+			envValue:  nil,
+			want:      FieldError{Field: "Value", EnvKey: "SIMPLEENV_TEST_FIELD_ERROR_MISSING", Constraint: "required", Value: "<unset>", Expected: "a value to set or to be marked as optional", Kind: ErrMissingRequired},
+		},
+		{
+			name:      "parse",
+			fieldType: reflect.TypeOf(false),
+			tag:       "SIMPLEENV_TEST_FIELD_ERROR_PARSE",
+			envValue:  strPtr("maybe"),
+			want:      FieldError{Field: "Value", EnvKey: "SIMPLEENV_TEST_FIELD_ERROR_PARSE", Value: "maybe", Expected: "a valid bool", Kind: ErrParse},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadSingleField(t, tt.fieldType, tt.tag, tt.envValue)
+
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("expected *FieldError, got %T: %v", err, err)
+			}
+			if !reflect.DeepEqual(*fieldErr, tt.want) {
+				t.Fatalf("unexpected field error: got %#v, want %#v", *fieldErr, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldErrorMessage(t *testing.T) {
+	err := &FieldError{Field: "Concurrency", EnvKey: "CONCURRENCY", Value: "abc", Expected: "a valid int", Kind: ErrParse}
+	want := `invalid value for field "Concurrency" from ENV["CONCURRENCY"]: got "abc", expected a valid int`
+	if err.Error() != want {
+		t.Fatalf("unexpected message: got %q, want %q", err.Error(), want)
+	}
+
+	cause := errors.New("boom")
+	err.Err = cause
+	if err.Error() != want+": boom" {
+		t.Fatalf("unexpected message with cause: %q", err.Error())
+	}
+	if !errors.Is(err, cause) || !errors.Is(err, ErrParse) {
+		t.Fatal("expected FieldError to unwrap to its kind and cause")
+	}
+}
+
+func TestFieldErrorMessageTruncatesLongValues(t *testing.T) {
+	value := strings.Repeat("x", maxErrorValueLen) + "tail"
+	err := &FieldError{Field: "Hosts", EnvKey: "HOSTS", Value: value, Expected: "a valid list", Kind: ErrParse}
+
+	want := fmt.Sprintf(`got %q (truncated, %d characters), expected`, strings.Repeat("x", maxErrorValueLen), len(value))
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected message to contain %q, got %q", want, err.Error())
+	}
+	if err.Value != value {
+		t.Fatalf("expected Value to keep the full raw value, got %q", err.Value)
+	}
+}
+
+func TestLoadAllFieldErrors(t *testing.T) {
+	type cfg struct {
+		Port int    `env:"SIMPLEENV_TEST_ALL_FIELD_ERROR_PORT;max=10"`
+		Name string `env:"SIMPLEENV_TEST_ALL_FIELD_ERROR_NAME"`
+	}
+
+	var c cfg
+	err := LoadAll(&c)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("expected joined error, got %T", err)
+	}
+	for _, fieldErr := range joined.Unwrap() {
+		var target *FieldError
+		if !errors.As(fieldErr, &target) {
+			t.Fatalf("expected *FieldError, got %T: %v", fieldErr, fieldErr)
+		}
+	}
+}
+
+func TestWithErrorFormatter(t *testing.T) {
+	type cfg struct {
+		Port int    `env:"SIMPLEENV_TEST_FORMAT_PORT;max=10"`
+		Name string `env:"SIMPLEENV_TEST_FORMAT_NAME"`
+	}
+
+	formatter := WithErrorFormatter(func(errs []*FieldError) string {
+		keys := make([]string, len(errs))
+		for i, fieldErr := range errs {
+			keys[i] = fieldErr.EnvKey
+		}
+		return "bad config: " + strings.Join(keys, ", ")
+	})
+
+	t.Run("formats every error of LoadAll", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_FORMAT_PORT", "80")
+		unsetEnv(t, "SIMPLEENV_TEST_FORMAT_NAME")
+
+		err := LoadAll(&cfg{}, formatter)
+		want := "bad config: SIMPLEENV_TEST_FORMAT_PORT, SIMPLEENV_TEST_FORMAT_NAME"
+		if err == nil || err.Error() != want {
+			t.Fatalf("expected %q, got %v", want, err)
+		}
+		if !errors.Is(err, ErrConstraint) || !errors.Is(err, ErrMissingRequired) {
+			t.Fatalf("expected formatted error to keep its kinds, got %v", err)
+		}
+	})
+
+	t.Run("formats the first error of Load", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_FORMAT_PORT", "80")
+		unsetEnv(t, "SIMPLEENV_TEST_FORMAT_NAME")
+
+		err := LoadWithOptions(&cfg{}, formatter)
+		var fieldErr *FieldError
+		if err == nil || err.Error() != "bad config: SIMPLEENV_TEST_FORMAT_PORT" || !errors.As(err, &fieldErr) {
+			t.Fatalf("expected formatted *FieldError, got %v", err)
+		}
+	})
+
+	t.Run("leaves other errors alone", func(t *testing.T) {
+		type badTag struct {
+			Port int `env:"SIMPLEENV_TEST_FORMAT_PORT;bogus"`
+		}
+
+		err := LoadWithOptions(&badTag{}, formatter)
+		if err == nil || strings.HasPrefix(err.Error(), "bad config") || !errors.Is(err, ErrInvalidTag) {
+			t.Fatalf("expected unformatted tag error, got %v", err)
+		}
+	})
+
+	t.Run("default format is unchanged", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_FORMAT_NAME")
+		t.Setenv("SIMPLEENV_TEST_FORMAT_PORT", "1")
+
+		err := LoadWithOptions(&cfg{})
+		if err == nil || !strings.Contains(err.Error(), `field "Name" from ENV["SIMPLEENV_TEST_FORMAT_NAME"]`) {
+			t.Fatalf("expected default message, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package simpleenv
+
+import (
+	"os"
+	"reflect"
+	"time"
+)
+
+// getValue reads key from the process environment and parses it with the
+// rules Load applies to a field of type T. ok is false when key is unset or
+// empty.
+func getValue[T any](key string) (value T, ok bool, err error) {
+	envValue, found := os.LookupEnv(key)
+	if !found || envValue == "" {
+		return value, false, nil
+	}
+
+	parsedValue, err := parseValue(key, reflect.TypeFor[T](), envTag{key: key}, envValue)
+	if err != nil {
+		return value, false, err
+	}
+
+	return parsedValue.Interface().(T), true, nil
+}
+
+// getOr returns the value of key, or fallback when key is unset, empty, or
+// invalid.
+func getOr[T any](key string, fallback T) T {
+	value, ok, err := getValue[T](key)
+	if !ok || err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+// mustGet returns the value of key and panics when key is unset, empty, or
+// invalid.
+func mustGet[T any](key string) T {
+	value, ok, err := getValue[T](key)
+	if err != nil {
+		panic(err)
+	}
+	if !ok {
+		panic(fieldMissingError(key, key))
+	}
+
+	return value
+}
+
+// GetString returns the environment variable named by key, or fallback when
+// it is unset or empty. It is meant for one-off lookups where declaring a
+// struct would be overkill:
+//
+//	addr := simpleenv.GetString("LISTEN_ADDR", ":8080")
+func GetString(key, fallback string) string {
+	return getOr(key, fallback)
+}
+
+// GetInt works like GetString for an int, parsed like an int field. Invalid
+// values also return fallback.
+//
+//	port := simpleenv.GetInt("PORT", 8080)
+func GetInt(key string, fallback int) int {
+	return getOr(key, fallback)
+}
+
+// GetBool works like GetInt for a bool.
+func GetBool(key string, fallback bool) bool {
+	return getOr(key, fallback)
+}
+
+// GetFloat works like GetInt for a float64.
+func GetFloat(key string, fallback float64) float64 {
+	return getOr(key, fallback)
+}
+
+// GetDuration works like GetInt for a time.Duration (for example: 500ms, 2s,
+// 1m).
+func GetDuration(key string, fallback time.Duration) time.Duration {
+	return getOr(key, fallback)
+}
+
+// MustGetString returns the environment variable named by key and panics with
+// a *FieldError when it is unset or empty.
+func MustGetString(key string) string {
+	return mustGet[string](key)
+}
+
+// MustGetInt works like MustGetString for an int, and also panics when the
+// value is not a valid int.
+func MustGetInt(key string) int {
+	return mustGet[int](key)
+}
+
+// MustGetBool works like MustGetInt for a bool.
+func MustGetBool(key string) bool {
+	return mustGet[bool](key)
+}
+
+// MustGetFloat works like MustGetInt for a float64.
+func MustGetFloat(key string) float64 {
+	return mustGet[float64](key)
+}
+
+// MustGetDuration works like MustGetInt for a time.Duration.
+func MustGetDuration(key string) time.Duration {
+	return mustGet[time.Duration](key)
+}
@@ -0,0 +1,87 @@
+package simpleenv
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGet(t *testing.T) {
+	t.Run("returns parsed values", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_GET_ADDR", ":9090")
+		t.Setenv("SIMPLEENV_TEST_GET_PORT", "0x1F90")
+		t.Setenv("SIMPLEENV_TEST_GET_DEBUG", "yes")
+		t.Setenv("SIMPLEENV_TEST_GET_RATIO", "0.25")
+		t.Setenv("SIMPLEENV_TEST_GET_TIMEOUT", "1m30s")
+
+		if got := GetString("SIMPLEENV_TEST_GET_ADDR", ":8080"); got != ":9090" {
+			t.Fatalf("GetString = %q, want %q", got, ":9090")
+		}
+		if got := GetInt("SIMPLEENV_TEST_GET_PORT", 80); got != 8080 {
+			t.Fatalf("GetInt = %d, want 8080", got)
+		}
+		if got := GetBool("SIMPLEENV_TEST_GET_DEBUG", false); !got {
+			t.Fatal("GetBool = false, want true")
+		}
+		if got := GetFloat("SIMPLEENV_TEST_GET_RATIO", 1); got != 0.25 {
+			t.Fatalf("GetFloat = %v, want 0.25", got)
+		}
+		if got := GetDuration("SIMPLEENV_TEST_GET_TIMEOUT", time.Second); got != 90*time.Second {
+			t.Fatalf("GetDuration = %v, want 1m30s", got)
+		}
+		if got := MustGetInt("SIMPLEENV_TEST_GET_PORT"); got != 8080 {
+			t.Fatalf("MustGetInt = %d, want 8080", got)
+		}
+	})
+
+	t.Run("falls back when unset, empty, or invalid", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_GET_UNSET")
+		t.Setenv("SIMPLEENV_TEST_GET_EMPTY", "")
+		t.Setenv("SIMPLEENV_TEST_GET_INVALID", "eighty")
+
+		if got := GetString("SIMPLEENV_TEST_GET_UNSET", "fallback"); got != "fallback" {
+			t.Fatalf("GetString = %q, want %q", got, "fallback")
+		}
+		if got := GetString("SIMPLEENV_TEST_GET_EMPTY", "fallback"); got != "fallback" {
+			t.Fatalf("GetString = %q, want %q", got, "fallback")
+		}
+		if got := GetInt("SIMPLEENV_TEST_GET_INVALID", 80); got != 80 {
+			t.Fatalf("GetInt = %d, want 80", got)
+		}
+		if got := GetDuration("SIMPLEENV_TEST_GET_INVALID", time.Second); got != time.Second {
+			t.Fatalf("GetDuration = %v, want 1s", got)
+		}
+	})
+
+	t.Run("MustGet panics with a FieldError", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_GET_UNSET")
+		t.Setenv("SIMPLEENV_TEST_GET_INVALID", "eighty")
+
+		tests := []struct {
+			name     string
+			fn       func()
+			wantKind error
+		}{
+			{name: "unset", fn: func() { MustGetString("SIMPLEENV_TEST_GET_UNSET") }, wantKind: ErrMissingRequired},
+			{name: "invalid", fn: func() { MustGetInt("SIMPLEENV_TEST_GET_INVALID") }, wantKind: ErrParse},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				defer func() {
+					err, ok := recover().(error)
+					if !ok {
+						t.Fatal("expected panic with error")
+					}
+
+					var fieldErr *FieldError
+					if !errors.As(err, &fieldErr) || !errors.Is(err, tt.wantKind) {
+						t.Fatalf("expected *FieldError wrapping %v, got %v", tt.wantKind, err)
+					}
+				}()
+
+				tt.fn()
+			})
+		}
+	})
+}
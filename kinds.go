@@ -0,0 +1,103 @@
+package simpleenv
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// kinds holds the constructors registered with RegisterKind, keyed by
+// kindKey.
+var kinds sync.Map
+
+type kindKey struct {
+	iface reflect.Type
+	name  string
+}
+
+// RegisterKind makes constructor available to fields of the interface type T:
+// when such a field's env value is name, Load assigns it constructor().
+// This lets the env select an implementation:
+//
+//	simpleenv.RegisterKind("redis", func() Backend { return &RedisBackend{} })
+//	simpleenv.RegisterKind("memory", func() Backend { return &MemoryBackend{} })
+//
+//	type AppEnv struct {
+//		Store Backend `env:"STORE_KIND"`
+//	}
+//
+// Values are matched exactly and go through the field's tag constraints
+// first. Validate and Marshal find a field's kind by calling the constructors
+// registered for T and comparing the dynamic types of the results. Register
+// kinds before loading, typically from an init function. RegisterKind panics
+// if T is not an interface type, if name is empty, if constructor is nil, or
+// if name is already registered for T.
+func RegisterKind[T any](name string, constructor func() T) {
+	iface := reflect.TypeFor[T]()
+	if iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("simpleenv: RegisterKind %q for %s, which is not an interface type", name, iface))
+	}
+	if name == "" {
+		panic(fmt.Sprintf("simpleenv: invalid kind name %q", name))
+	}
+	if constructor == nil {
+		panic(fmt.Sprintf("simpleenv: RegisterKind %q with nil func", name))
+	}
+
+	newValue := func() any { return constructor() }
+	if _, loaded := kinds.LoadOrStore(kindKey{iface: iface, name: name}, newValue); loaded {
+		panic(fmt.Sprintf("simpleenv: RegisterKind called twice for %q (%s)", name, iface))
+	}
+}
+
+// kindNames returns the sorted names of the kinds registered for iface.
+func kindNames(iface reflect.Type) []string {
+	var names []string
+	kinds.Range(func(key, _ any) bool {
+		if k := key.(kindKey); k.iface == iface {
+			names = append(names, k.name)
+		}
+
+		return true
+	})
+	slices.Sort(names)
+
+	return names
+}
+
+// parseKind assigns the result of the constructor registered for envValue to
+// a value of the interface type iface.
+func parseKind(fieldName string, iface reflect.Type, envKey, envValue string) (reflect.Value, error) {
+	newValue, ok := kinds.Load(kindKey{iface: iface, name: envValue})
+	if !ok {
+		names := kindNames(iface)
+		if len(names) == 0 {
+			return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, fmt.Sprintf("a kind registered for %s with RegisterKind (none are registered)", iface))
+		}
+
+		return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, fmt.Sprintf("one of the kinds registered for %s: [%s]", iface, strings.Join(names, ",")))
+	}
+
+	value := reflect.New(iface).Elem()
+	if kind := newValue.(func() any)(); kind != nil {
+		value.Set(reflect.ValueOf(kind))
+	}
+
+	return value, nil
+}
+
+// formatKind returns the name of the kind whose constructor returns the
+// dynamic type of fieldValue, a non-nil interface value.
+func formatKind(fieldName string, fieldValue reflect.Value, envKey string) (string, error) {
+	iface, dynamicType := fieldValue.Type(), fieldValue.Elem().Type()
+	for _, name := range kindNames(iface) {
+		newValue, _ := kinds.Load(kindKey{iface: iface, name: name})
+		if reflect.TypeOf(newValue.(func() any)()) == dynamicType {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("failed to marshal field %q (ENV[%q]): no kind registered for %s returns %s", fieldName, envKey, iface, dynamicType)
+}
@@ -0,0 +1,125 @@
+package simpleenv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testBackend interface {
+	Name() string
+}
+
+type testRedisBackend struct{}
+
+func (*testRedisBackend) Name() string { return "redis" }
+
+type testMemoryBackend struct{}
+
+func (testMemoryBackend) Name() string { return "memory" }
+
+type testUnregisteredBackend struct{}
+
+func (testUnregisteredBackend) Name() string { return "other" }
+
+type testEmptyInterface interface {
+	Empty()
+}
+
+func init() {
+	RegisterKind("redis", func() testBackend { return &testRedisBackend{} })
+	RegisterKind("memory", func() testBackend { return testMemoryBackend{} })
+}
+
+func TestLoadKinds(t *testing.T) {
+	type cfg struct {
+		Store testBackend `env:"STORE_KIND"`
+		Cache testBackend `env:"CACHE_KIND;optional;oneof=memory"`
+	}
+
+	t.Run("constructs the registered kind", func(t *testing.T) {
+		var c cfg
+		err := LoadFrom(&c, map[string]string{"STORE_KIND": "redis", "CACHE_KIND": "memory"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, ok := c.Store.(*testRedisBackend); !ok {
+			t.Fatalf("expected a redis backend, got %#v", c.Store)
+		}
+		if c.Cache == nil || c.Cache.Name() != "memory" {
+			t.Fatalf("expected a memory backend, got %#v", c.Cache)
+		}
+	})
+
+	t.Run("unknown kinds list the registered ones", func(t *testing.T) {
+		err := LoadFrom(&cfg{}, map[string]string{"STORE_KIND": "etcd"})
+		want := `invalid value for field "Store" from ENV["STORE_KIND"]: got "etcd", expected one of the kinds registered for simpleenv.testBackend: [memory,redis]`
+		if !errors.Is(err, ErrParse) || err.Error() != want {
+			t.Fatalf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("tag constraints apply to the kind name", func(t *testing.T) {
+		err := LoadFrom(&cfg{}, map[string]string{"STORE_KIND": "redis", "CACHE_KIND": "redis"})
+		if !errors.Is(err, ErrConstraint) {
+			t.Fatalf("expected constraint error, got %v", err)
+		}
+	})
+
+	t.Run("interfaces without kinds", func(t *testing.T) {
+		type emptyCfg struct {
+			Value testEmptyInterface `env:"VALUE"`
+		}
+
+		err := LoadFrom(&emptyCfg{}, map[string]string{"VALUE": "x"})
+		if err == nil || !strings.Contains(err.Error(), "a kind registered for simpleenv.testEmptyInterface with RegisterKind (none are registered)") {
+			t.Fatalf("expected no kinds error, got %v", err)
+		}
+	})
+
+	t.Run("Validate and Marshal use the kind name", func(t *testing.T) {
+		c := cfg{Store: &testRedisBackend{}}
+		if err := Validate(&c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := Validate(&cfg{}); !errors.Is(err, ErrMissingRequired) {
+			t.Fatalf("expected missing required error for a nil interface, got %v", err)
+		}
+
+		out, err := Marshal(&c)
+		if err != nil || out != "STORE_KIND=redis\n# CACHE_KIND=\n" {
+			t.Fatalf("unexpected marshal output %q (%v)", out, err)
+		}
+
+		_, err = Marshal(&cfg{Store: testUnregisteredBackend{}})
+		if err == nil || !strings.Contains(err.Error(), "no kind registered for simpleenv.testBackend returns simpleenv.testUnregisteredBackend") {
+			t.Fatalf("expected marshal error, got %v", err)
+		}
+	})
+}
+
+func TestRegisterKindPanics(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func()
+		want     string
+	}{
+		{name: "empty name", register: func() { RegisterKind("", func() testBackend { return nil }) }, want: "invalid kind name"},
+		{name: "nil func", register: func() { RegisterKind[testBackend]("nil", nil) }, want: "nil func"},
+		{name: "duplicate", register: func() { RegisterKind("redis", func() testBackend { return nil }) }, want: "called twice"},
+		{name: "not an interface", register: func() { RegisterKind("int", func() int { return 0 }) }, want: "not an interface type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if r == nil || !strings.Contains(r.(string), tt.want) {
+					t.Fatalf("expected panic containing %q, got %v", tt.want, r)
+				}
+			}()
+
+			tt.register()
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package simpleenv
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Marshal renders the tagged fields of envConfig as KEY=value lines, the
+// inverse of Load. It is handy for writing a sample .env file or dumping the
+// effective configuration.
+//
+//	out, err := simpleenv.Marshal(&cfg)
+//	// PORT=8080
+//	// HOSTS=a.example,b.example
+//	// # TIMEOUT=0s
+//
+// Values are formatted the way Load reads them (durations as "1m30s",
+// []string joined by its separator) and double-quoted when they contain
+// spaces or special characters. Nil pointers and zero values of optional
+// fields are written as commented-out lines. Options such as WithTagName and
+// WithTagSeparator select how tags are read, as for Load.
+func Marshal(envConfig any, opts ...Option) (string, error) {
+	v := reflect.ValueOf(envConfig)
+	if v.Kind() == reflect.Struct {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		envConfig = ptr.Interface()
+	}
+
+	e, err := structElem(envConfig)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	err = newLoader(opts).marshalStruct(&b, e, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+func (l *loader) marshalStruct(b *strings.Builder, structValue reflect.Value, fieldPath, keyPrefix string) error {
+	t := structValue.Type()
+
+	for i := range t.NumField() {
+		fieldType := t.Field(i)
+		if !isFieldVisible(fieldType) {
+			continue
+		}
+		fieldType.Name = fieldPath + fieldType.Name
+
+		err := l.marshalField(b, fieldType, structValue.Field(i), keyPrefix)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *loader) marshalField(b *strings.Builder, fieldType reflect.StructField, fieldValue reflect.Value, keyPrefix string) error {
+	fieldTag, err := parseEnvTag(fieldType, l.tagName, l.tagSeparator)
+	if err != nil {
+		return err
+	}
+	if fieldTag.nested {
+		return l.marshalStruct(b, nestedStruct(fieldValue, false), nestedFieldPath(fieldType), keyPrefix+fieldTag.prefix)
+	}
+	if !fieldTag.hasTag {
+		return nil
+	}
+
+	fieldTag.addKeyPrefix(keyPrefix)
+
+	if fieldTag.collect {
+		marshalCollectedField(b, fieldValue, fieldTag)
+		return nil
+	}
+
+	unset := false
+	if kind := fieldValue.Kind(); kind == reflect.Pointer || kind == reflect.Interface {
+		if fieldValue.IsNil() {
+			b.WriteString("# " + fieldTag.key + "=\n")
+			return nil
+		}
+
+		if kind == reflect.Pointer {
+			fieldValue = fieldValue.Elem()
+		}
+	} else {
+		unset = fieldTag.optional && fieldValue.IsZero()
+	}
+
+	value, err := formatFieldValue(fieldType.Name, fieldValue, fieldTag)
+	if err != nil {
+		return err
+	}
+
+	if unset {
+		b.WriteString("# ")
+	}
+	b.WriteString(fieldTag.key + "=" + quoteEnvValue(value) + "\n")
+	return nil
+}
+
+// marshalCollectedField writes one line per map entry of a collect field,
+// sorted by key.
+func marshalCollectedField(b *strings.Builder, fieldValue reflect.Value, fieldTag envTag) {
+	if fieldValue.Len() == 0 {
+		b.WriteString("# " + fieldTag.key + "*=\n")
+		return
+	}
+
+	keys := make([]string, 0, fieldValue.Len())
+	for _, key := range fieldValue.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		value := fieldValue.MapIndex(reflect.ValueOf(key).Convert(fieldValue.Type().Key()))
+		b.WriteString(fieldTag.key + key + "=" + quoteEnvValue(value.String()) + "\n")
+	}
+}
+
+// quoteEnvValue double-quotes value when it contains characters that a .env
+// parser would otherwise split, strip, or expand.
+func quoteEnvValue(value string) string {
+	needsQuotes := strings.ContainsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_-.,:/@+=%|*~^", r)
+	})
+	if !needsQuotes {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "$", `\$`)
+	return `"` + replacer.Replace(value) + `"`
+}
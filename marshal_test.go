@@ -0,0 +1,159 @@
+package simpleenv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type marshalDBConfig struct {
+	Host string `env:"HOST"`
+	Port int    `env:"PORT;min=1"`
+}
+
+type marshalConfig struct {
+	Name     string          `env:"SIMPLEENV_TEST_MARSHAL_NAME"`
+	Greeting string          `env:"SIMPLEENV_TEST_MARSHAL_GREETING"`
+	Debug    bool            `env:"SIMPLEENV_TEST_MARSHAL_DEBUG"`
+	Ratio    float64         `env:"SIMPLEENV_TEST_MARSHAL_RATIO"`
+	Timeout  time.Duration   `env:"SIMPLEENV_TEST_MARSHAL_TIMEOUT"`
+	Hosts    []string        `env:"SIMPLEENV_TEST_MARSHAL_HOSTS;sep=|"`
+	Started  time.Time       `env:"SIMPLEENV_TEST_MARSHAL_STARTED;layout=2006-01-02"`
+	Retries  int             `env:"SIMPLEENV_TEST_MARSHAL_RETRIES;optional"`
+	Token    *string         `env:"SIMPLEENV_TEST_MARSHAL_TOKEN;optional"`
+	DB       marshalDBConfig `env:";prefix=SIMPLEENV_TEST_MARSHAL_DB_"`
+	internal string
+	Cache    map[string]string
+}
+
+func newMarshalConfig() marshalConfig {
+	return marshalConfig{
+		Name:     "api",
+		Greeting: `say "hi" to $USER`,
+		Debug:    true,
+		Ratio:    0.25,
+		Timeout:  90 * time.Second,
+		Hosts:    []string{"a.example", "b.example"},
+		Started:  time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		DB:       marshalDBConfig{Host: "db.local", Port: 5432},
+		internal: "skipped",
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	c := newMarshalConfig()
+
+	got, err := Marshal(&c)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := `SIMPLEENV_TEST_MARSHAL_NAME=api
+SIMPLEENV_TEST_MARSHAL_GREETING="say \"hi\" to \$USER"
+SIMPLEENV_TEST_MARSHAL_DEBUG=true
+SIMPLEENV_TEST_MARSHAL_RATIO=0.25
+SIMPLEENV_TEST_MARSHAL_TIMEOUT=1m30s
+SIMPLEENV_TEST_MARSHAL_HOSTS=a.example|b.example
+SIMPLEENV_TEST_MARSHAL_STARTED=2024-01-02
+# SIMPLEENV_TEST_MARSHAL_RETRIES=0
+# SIMPLEENV_TEST_MARSHAL_TOKEN=
+SIMPLEENV_TEST_MARSHAL_DB_HOST=db.local
+SIMPLEENV_TEST_MARSHAL_DB_PORT=5432
+`
+	if got != want {
+		t.Fatalf("unexpected output:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	byValue, err := Marshal(c)
+	if err != nil {
+		t.Fatalf("expected no error for struct value, got %v", err)
+	}
+	if byValue != got {
+		t.Fatalf("expected struct value to marshal like a pointer, got:\n%s", byValue)
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	c := newMarshalConfig()
+	c.Retries = 3
+	c.Token = strPtr("a b\nc\\d")
+	c.internal = ""
+
+	out, err := Marshal(&c)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	values, err := godotenv.Unmarshal(out)
+	if err != nil {
+		t.Fatalf("expected marshaled output to parse, got %v\n%s", err, out)
+	}
+
+	var loaded marshalConfig
+	if err := LoadFrom(&loaded, values); err != nil {
+		t.Fatalf("expected marshaled output to load, got %v\n%s", err, out)
+	}
+	if !reflect.DeepEqual(loaded, c) {
+		t.Fatalf("round trip mismatch:\ngot:  %+v\nwant: %+v", loaded, c)
+	}
+}
+
+func TestQuoteEnvValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: ""},
+		{value: "plain", want: "plain"},
+		{value: "postgres://user@host:5432/db", want: "postgres://user@host:5432/db"},
+		{value: "with space", want: `"with space"`},
+		{value: "a#b", want: `"a#b"`},
+		{value: `back\slash`, want: `"back\\slash"`},
+		{value: "line\nbreak", want: `"line\nbreak"`},
+		{value: "${HOME}", want: `"\${HOME}"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := quoteEnvValue(tt.value); got != tt.want {
+				t.Fatalf("quoteEnvValue(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalTagOptions(t *testing.T) {
+	type commaCfg struct {
+		Token string `env:"TOKEN,secret"`
+		Port  int    `env:"PORT,min=10"`
+	}
+	type cfgTag struct {
+		Port int `cfg:"PORT;min=10"`
+	}
+
+	out, err := Marshal(&commaCfg{Token: "abc", Port: 10}, WithTagSeparator(','))
+	if err != nil || out != "TOKEN=abc\nPORT=10\n" {
+		t.Fatalf("unexpected marshal output %q (%v)", out, err)
+	}
+
+	out, err = Marshal(cfgTag{Port: 10}, WithTagName("cfg"))
+	if err != nil || out != "PORT=10\n" {
+		t.Fatalf("unexpected marshal output %q (%v)", out, err)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	if _, err := Marshal(nil); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected errors.Is(err, ErrInvalidInput), got %v", err)
+	}
+
+	type cfg struct {
+		Values map[string]string `env:"SIMPLEENV_TEST_MARSHAL_MAP"`
+	}
+	if _, err := Marshal(&cfg{}); !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("expected errors.Is(err, ErrUnsupportedType), got %v", err)
+	}
+}
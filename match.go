@@ -0,0 +1,79 @@
+package simpleenv
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// fieldMatch is a field tagged with eq=Other, checked against the Other field
+// once every field of the struct has been loaded.
+type fieldMatch struct {
+	fieldType  reflect.StructField
+	fieldValue reflect.Value
+	fieldTag   envTag
+	target     reflect.Value
+}
+
+// lookupFieldRef returns the index of the sibling field named by the eq=
+// option of fieldTag, relative to structType. The field must exist, be
+// exported, and have the same type as fieldType.
+func lookupFieldRef(structType reflect.Type, fieldType reflect.StructField, fieldTag envTag) ([]int, error) {
+	target, ok := structType.FieldByName(fieldTag.eqField)
+	if !ok || !target.IsExported() {
+		return nil, tagError(fieldType.Name, fieldTag.key, "eq=%s refers to an unknown field", fieldTag.eqField)
+	}
+	if slices.Equal(target.Index, fieldType.Index) {
+		return nil, tagError(fieldType.Name, fieldTag.key, "eq=%s refers to the field itself", fieldTag.eqField)
+	}
+	if target.Type != fieldType.Type {
+		return nil, tagError(fieldType.Name, fieldTag.key, "eq=%s refers to a field of type %s, expected %s", fieldTag.eqField, target.Type, fieldType.Type)
+	}
+
+	return target.Index, nil
+}
+
+// checkFieldMatch returns a constraint error when the field differs from the
+// field it references. Nil pointers and zero values of optional fields are
+// treated as unset and skipped, as in Validate.
+func checkFieldMatch(m fieldMatch) error {
+	fieldValue := m.fieldValue
+	if fieldValue.Kind() == reflect.Pointer && fieldValue.IsNil() {
+		return nil
+	}
+	if m.fieldTag.optional && fieldValue.IsZero() {
+		return nil
+	}
+	if reflect.DeepEqual(fieldValue.Interface(), m.target.Interface()) {
+		return nil
+	}
+
+	if fieldValue.Kind() == reflect.Pointer {
+		fieldValue = fieldValue.Elem()
+	}
+	value, err := formatFieldValue(m.fieldType.Name, fieldValue, m.fieldTag)
+	if err != nil {
+		return err
+	}
+
+	constraint := "eq=" + m.fieldTag.eqField
+	return redactFieldError(fieldConstraintError(m.fieldType.Name, m.fieldTag.key, value, constraint, fmt.Sprintf("the same value as field %q", m.fieldTag.eqField)), m.fieldTag)
+}
+
+// checkFieldMatches runs the eq= checks recorded while loading.
+func (l *loader) checkFieldMatches() error {
+	for _, m := range l.matches {
+		err := checkFieldMatch(m)
+		if err == nil {
+			continue
+		}
+
+		if !l.collectErrors {
+			return err
+		}
+
+		l.errs = append(l.errs, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package simpleenv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadFieldMatch(t *testing.T) {
+	type cfg struct {
+		PasswordConfirm string `env:"PASSWORD_CONFIRM;eq=Password;secret"`
+		Password        string `env:"PASSWORD;secret"`
+	}
+
+	t.Run("matching values load", func(t *testing.T) {
+		var c cfg
+		err := LoadFrom(&c, map[string]string{"PASSWORD": "hunter2", "PASSWORD_CONFIRM": "hunter2"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("differing values fail after every field loads", func(t *testing.T) {
+		var c cfg
+		err := LoadFrom(&c, map[string]string{"PASSWORD": "hunter2", "PASSWORD_CONFIRM": "hunter3"})
+		if !errors.Is(err, ErrConstraint) {
+			t.Fatalf("expected constraint error, got %v", err)
+		}
+
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Constraint != "eq=Password" || fieldErr.Field != "PasswordConfirm" {
+			t.Fatalf("unexpected field error %#v", fieldErr)
+		}
+		if want := `expected the same value as field "Password"`; !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %v", want, err)
+		}
+		if strings.Contains(err.Error(), "hunter") {
+			t.Fatalf("expected secret value to be masked, got %v", err)
+		}
+	})
+
+	t.Run("LoadAll reports mismatches with other errors", func(t *testing.T) {
+		type allCfg struct {
+			Port        int    `env:"SIMPLEENV_TEST_MATCH_PORT"`
+			Token       string `env:"SIMPLEENV_TEST_MATCH_TOKEN"`
+			TokenRepeat string `env:"SIMPLEENV_TEST_MATCH_TOKEN_REPEAT;eq=Token"`
+		}
+		t.Setenv("SIMPLEENV_TEST_MATCH_PORT", "http")
+		t.Setenv("SIMPLEENV_TEST_MATCH_TOKEN", "a")
+		t.Setenv("SIMPLEENV_TEST_MATCH_TOKEN_REPEAT", "b")
+
+		err := LoadAll(&allCfg{})
+		if err == nil || !strings.Contains(err.Error(), `ENV["SIMPLEENV_TEST_MATCH_PORT"]`) || !strings.Contains(err.Error(), `expected the same value as field "Token"`) {
+			t.Fatalf("expected parse and eq errors, got %v", err)
+		}
+	})
+
+	t.Run("nested and non-string fields", func(t *testing.T) {
+		type db struct {
+			Port        int `env:"PORT"`
+			ReplicaPort int `env:"REPLICA_PORT;eq=Port"`
+		}
+		type nestedCfg struct {
+			DB db `env:";prefix=DB_"`
+		}
+
+		var c nestedCfg
+		err := LoadFrom(&c, map[string]string{"DB_PORT": "5432", "DB_REPLICA_PORT": "0x1538"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		err = LoadFrom(&c, map[string]string{"DB_PORT": "5432", "DB_REPLICA_PORT": "5433"})
+		if err == nil || !strings.Contains(err.Error(), `invalid value for field "DB.ReplicaPort" from ENV["DB_REPLICA_PORT"]: got "5433"`) {
+			t.Fatalf("expected nested eq error, got %v", err)
+		}
+	})
+
+	t.Run("unset optional fields are skipped", func(t *testing.T) {
+		type optionalCfg struct {
+			Password        string `env:"PASSWORD"`
+			PasswordConfirm string `env:"PASSWORD_CONFIRM;optional;eq=Password"`
+		}
+
+		err := LoadFrom(&optionalCfg{}, map[string]string{"PASSWORD": "hunter2"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("LoadOrDefault skips missing fields", func(t *testing.T) {
+		warnings, err := LoadOrDefault(&cfg{}, WithSource(MapSource{"PASSWORD": "hunter2"}))
+		if err != nil || len(warnings) != 1 {
+			t.Fatalf("expected one warning and no error, got %v, %v", warnings, err)
+		}
+	})
+
+	t.Run("Validate and Schema compare fields", func(t *testing.T) {
+		if err := Validate(&cfg{Password: "a", PasswordConfirm: "a"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := Validate(&cfg{Password: "a", PasswordConfirm: "b"}); !errors.Is(err, ErrConstraint) {
+			t.Fatalf("expected constraint error, got %v", err)
+		}
+
+		schema, err := Compile[cfg](WithSource(MapSource{"PASSWORD": "a", "PASSWORD_CONFIRM": "b"}))
+		if err != nil {
+			t.Fatalf("expected no compile error, got %v", err)
+		}
+		if err := schema.Load(&cfg{}); !errors.Is(err, ErrConstraint) {
+			t.Fatalf("expected constraint error, got %v", err)
+		}
+	})
+}
+
+func TestFieldMatchTagErrors(t *testing.T) {
+	type unknownCfg struct {
+		Confirm string `env:"CONFIRM;eq=Missing"`
+	}
+	type selfCfg struct {
+		Confirm string `env:"CONFIRM;eq=Confirm"`
+	}
+	type typeCfg struct {
+		Port    int    `env:"PORT"`
+		Confirm string `env:"CONFIRM;eq=Port"`
+	}
+	type emptyCfg struct {
+		Confirm string `env:"CONFIRM;eq="`
+	}
+
+	source := map[string]string{"PORT": "1", "CONFIRM": "1"}
+	tests := []struct {
+		name    string
+		load    func() error
+		wantErr string
+	}{
+		{name: "unknown field", load: func() error { return LoadFrom(&unknownCfg{}, source) }, wantErr: "eq=Missing refers to an unknown field"},
+		{name: "self reference", load: func() error { return LoadFrom(&selfCfg{}, source) }, wantErr: "eq=Confirm refers to the field itself"},
+		{name: "type mismatch", load: func() error { return LoadFrom(&typeCfg{}, source) }, wantErr: "eq=Port refers to a field of type int, expected string"},
+		{name: "empty reference", load: func() error { return LoadFrom(&emptyCfg{}, source) }, wantErr: "eq must name a field"},
+		{name: "compile", load: func() error { _, err := Compile[unknownCfg](); return err }, wantErr: "eq=Missing refers to an unknown field"},
+		{name: "validate", load: func() error { return Validate(&typeCfg{}) }, wantErr: "refers to a field of type int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.load()
+			if !errors.Is(err, ErrInvalidTag) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected tag error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
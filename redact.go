@@ -0,0 +1,136 @@
+package simpleenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+const redactedValue = "****"
+
+// Redacted renders envConfig like fmt's %+v verb, with the values of fields
+// tagged `secret` replaced by "****", so the effective configuration can be
+// logged safely at startup.
+//
+//	type AppEnv struct {
+//		Port     int    `env:"PORT"`
+//		APIToken string `env:"API_TOKEN;secret"`
+//		DBPass   string `env:"DB_PASSWORD;secret=2"`
+//	}
+//
+//	simpleenv.Redacted(&cfg) // &{Port:8080 APIToken:**** DBPass:hu****er}
+//
+// secret=N keeps the first and last N characters for debugging when the value
+// is longer than 2*N characters. Empty secrets and nil pointers are shown as
+// is. Fields with an invalid tag are masked as well. Pass the WithTagName and
+// WithTagSeparator options used to load envConfig, so its secret fields are
+// recognized.
+func Redacted(envConfig any, opts ...Option) string {
+	v := reflect.ValueOf(envConfig)
+	if v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		return "&" + newLoader(opts).redactStruct(v.Elem())
+	}
+	if v.Kind() == reflect.Struct {
+		return newLoader(opts).redactStruct(v)
+	}
+
+	return fmt.Sprintf("%+v", envConfig)
+}
+
+func (l *loader) redactStruct(structValue reflect.Value) string {
+	t := structValue.Type()
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := range t.NumField() {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+
+		fieldType := t.Field(i)
+		b.WriteString(fieldType.Name + ":")
+		b.WriteString(l.redactField(fieldType, structValue.Field(i)))
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+func (l *loader) redactField(fieldType reflect.StructField, fieldValue reflect.Value) string {
+	if !isFieldVisible(fieldType) {
+		return fmt.Sprintf("%+v", fieldValue)
+	}
+
+	fieldTag, err := parseEnvTag(fieldType, l.tagName, l.tagSeparator)
+	if err != nil {
+		return redactedValue
+	}
+	if fieldTag.nested {
+		return l.redactStruct(nestedStruct(fieldValue, false))
+	}
+	if !fieldTag.secret {
+		return fmt.Sprintf("%+v", fieldValue)
+	}
+
+	if fieldValue.Kind() == reflect.Pointer {
+		if fieldValue.IsNil() {
+			return "<nil>"
+		}
+
+		fieldValue = fieldValue.Elem()
+	}
+
+	return redactValue(fmt.Sprint(fieldValue), fieldTag.secretReveal)
+}
+
+// redactFieldError masks the value carried by a secret field's FieldError, so
+// the secret does not end up in logs. The message of its underlying error,
+// which often quotes the raw value (as url.Parse and UnmarshalText errors
+// do), is left out of Error as well.
+func redactFieldError(err error, fieldTag envTag) error {
+	var fieldErr *FieldError
+	if fieldTag.secret && errors.As(err, &fieldErr) {
+		fieldErr.Value = redactValue(fieldErr.Value, fieldTag.secretReveal)
+		if fieldErr.Err != nil && !isRedactedError(fieldErr.Err) {
+			fieldErr.Err = &redactedError{err: fieldErr.Err}
+		}
+	}
+
+	return err
+}
+
+// redactedError hides the message of a secret field's underlying error while
+// keeping it available to errors.Is and errors.As.
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return redactedValue
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+func isRedactedError(err error) bool {
+	_, ok := err.(*redactedError)
+	return ok
+}
+
+// redactValue masks value, keeping reveal characters at each end when the
+// value is long enough that doing so still hides most of it.
+func redactValue(value string, reveal int) string {
+	if value == "" {
+		return ""
+	}
+
+	if reveal == 0 || utf8.RuneCountInString(value) <= 2*reveal {
+		return redactedValue
+	}
+
+	runes := []rune(value)
+	return string(runes[:reveal]) + redactedValue + string(runes[len(runes)-reveal:])
+}
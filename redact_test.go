@@ -0,0 +1,185 @@
+package simpleenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedacted(t *testing.T) {
+	type dbConfig struct {
+		Host     string `env:"HOST"`
+		Password string `env:"PASSWORD;secret=2"`
+	}
+
+	type cfg struct {
+		Port     int           `env:"PORT"`
+		APIToken string        `env:"API_TOKEN;secret"`
+		Key      *string       `env:"KEY;optional;secret"`
+		Empty    string        `env:"EMPTY;allowempty;secret"`
+		Timeout  time.Duration `env:"TIMEOUT"`
+		DB       dbConfig      `env:";prefix=DB_"`
+		note     string
+	}
+
+	c := cfg{
+		Port:     8080,
+		APIToken: "sk-live-123456",
+		Timeout:  time.Second,
+		DB:       dbConfig{Host: "db.local", Password: "hunter22"},
+		note:     "internal",
+	}
+
+	want := "{Port:8080 APIToken:**** Key:<nil> Empty: Timeout:1s DB:{Host:db.local Password:hu****22} note:internal}"
+	if got := Redacted(c); got != want {
+		t.Fatalf("unexpected redacted value:\ngot:  %s\nwant: %s", got, want)
+	}
+	if got := Redacted(&c); got != "&"+want {
+		t.Fatalf("unexpected redacted pointer value:\ngot:  %s\nwant: &%s", got, want)
+	}
+
+	c.Key = strPtr("secret-key")
+	if got := Redacted(&c); !strings.Contains(got, "Key:****") || strings.Contains(got, "secret-key") {
+		t.Fatalf("expected pointer secret to be masked, got %s", got)
+	}
+}
+
+func TestRedactedTagOptions(t *testing.T) {
+	type commaCfg struct {
+		Token string `env:"TOKEN,secret"`
+	}
+	type cfgTag struct {
+		Token string `cfg:"TOKEN;secret"`
+	}
+
+	if got := Redacted(&commaCfg{Token: "supersecret"}, WithTagSeparator(',')); got != "&{Token:****}" {
+		t.Fatalf("expected comma-separated secret to be masked, got %s", got)
+	}
+	if got := Redacted(cfgTag{Token: "supersecret"}, WithTagName("cfg")); got != "{Token:****}" {
+		t.Fatalf("expected secret under a custom tag name to be masked, got %s", got)
+	}
+}
+
+func TestRedactValue(t *testing.T) {
+	tests := []struct {
+		value  string
+		reveal int
+		want   string
+	}{
+		{value: "", reveal: 0, want: ""},
+		{value: "abcdef", reveal: 0, want: "****"},
+		{value: "abcdef", reveal: 2, want: "ab****ef"},
+		{value: "abcd", reveal: 2, want: "****"},
+		{value: "héllowörld", reveal: 1, want: "h****d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := redactValue(tt.value, tt.reveal); got != tt.want {
+				t.Fatalf("redactValue(%q, %d) = %q, want %q", tt.value, tt.reveal, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSecretTag(t *testing.T) {
+	type cfg struct {
+		Token string `env:"SIMPLEENV_TEST_SECRET_TOKEN;secret=2;minlen=4"`
+	}
+
+	t.Setenv("SIMPLEENV_TEST_SECRET_TOKEN", "abcdef")
+
+	var c cfg
+	if err := Load(&c); err != nil {
+		t.Fatalf("expected secret tag to be accepted, got %v", err)
+	}
+	if c.Token != "abcdef" {
+		t.Fatalf("expected secret value to load unchanged, got %q", c.Token)
+	}
+
+	type invalid struct {
+		Token string `env:"SIMPLEENV_TEST_SECRET_TOKEN;secret=-1"`
+	}
+	err := Load(&invalid{})
+	if !errors.Is(err, ErrInvalidTag) || !strings.Contains(err.Error(), "secret must be a non-negative integer") {
+		t.Fatalf("expected invalid secret tag error, got %v", err)
+	}
+}
+
+var errBadAPIKey = errors.New("bad API key")
+
+// apiKey fails to unmarshal with an error that quotes the raw value.
+type apiKey string
+
+func (k *apiKey) UnmarshalText(text []byte) error {
+	if !strings.HasPrefix(string(text), "sk_") {
+		return fmt.Errorf("%w %q", errBadAPIKey, text)
+	}
+
+	*k = apiKey(text)
+	return nil
+}
+
+func TestSecretFieldErrorsMaskValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType reflect.Type
+		tag       string
+		envValue  string
+		wantValue string
+	}{
+		{name: "parse error", fieldType: reflect.TypeOf(0), tag: "SIMPLEENV_TEST_SECRET_PIN;secret", envValue: "12a4", wantValue: "****"},
+		{name: "constraint error", fieldType: reflect.TypeOf(""), tag: "SIMPLEENV_TEST_SECRET_KEY;secret;minlen=16", envValue: "hunter2", wantValue: "****"},
+		{name: "partial reveal", fieldType: reflect.TypeOf(""), tag: "SIMPLEENV_TEST_SECRET_KEY;secret=2;prefix=sk_", envValue: "pk_live_abcdef", wantValue: "pk****ef"},
+		{name: "text unmarshaler error", fieldType: reflect.TypeOf(apiKey("")), tag: "SIMPLEENV_TEST_SECRET_API_KEY;secret", envValue: "pk_live_abcdef", wantValue: "****"},
+		{name: "invalid default", fieldType: reflect.TypeOf(0), tag: "SIMPLEENV_TEST_SECRET_UNSET;secret;default=12a4", wantValue: "****"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envValue := strPtr(tt.envValue)
+			if tt.envValue == "" {
+				envValue = nil
+			}
+
+			_, err := loadSingleField(t, tt.fieldType, tt.tag, envValue)
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("expected *FieldError, got %T: %v", err, err)
+			}
+			if fieldErr.Value != tt.wantValue {
+				t.Fatalf("expected masked value %q, got %q", tt.wantValue, fieldErr.Value)
+			}
+			if raw := tt.envValue; raw != "" && strings.Contains(err.Error(), raw) {
+				t.Fatalf("expected error not to contain the secret, got %q", err.Error())
+			}
+			if strings.Contains(err.Error(), "12a4") {
+				t.Fatalf("expected error not to contain the default, got %q", err.Error())
+			}
+		})
+	}
+
+	t.Run("underlying error still unwraps", func(t *testing.T) {
+		_, err := loadSingleField(t, reflect.TypeOf(apiKey("")), "SIMPLEENV_TEST_SECRET_API_KEY;secret", strPtr("pk_live_abcdef"))
+		if !errors.Is(err, errBadAPIKey) || !errors.Is(err, ErrParse) {
+			t.Fatalf("expected error to unwrap to its cause, got %v", err)
+		}
+		if strings.Contains(err.Error(), "bad API key") {
+			t.Fatalf("expected the cause's message to be left out, got %q", err.Error())
+		}
+	})
+
+	t.Run("Validate masks the value", func(t *testing.T) {
+		type cfg struct {
+			Key string `env:"SIMPLEENV_TEST_SECRET_KEY;secret;minlen=16"`
+		}
+
+		err := Validate(&cfg{Key: "hunter2"})
+		if err == nil || strings.Contains(err.Error(), "hunter2") || !strings.Contains(err.Error(), `got "****"`) {
+			t.Fatalf("expected masked constraint error, got %v", err)
+		}
+	})
+}
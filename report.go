@@ -0,0 +1,64 @@
+package simpleenv
+
+import (
+	"reflect"
+)
+
+// Origin describes where a field's value came from.
+type Origin string
+
+const (
+	// OriginSource means the value was read from the field's env key.
+	OriginSource Origin = "source"
+	// OriginAlias means the value was read from one of the field's alias keys.
+	OriginAlias Origin = "alias"
+	// OriginSecretFile means the value was read from the file named by KEY_FILE.
+	OriginSecretFile Origin = "file"
+	// OriginDefault means the key was unset and the tag default was used.
+	OriginDefault Origin = "default"
+	// OriginUnset means no value was found, so the field kept its value.
+	OriginUnset Origin = "unset"
+)
+
+// ResolvedValue describes how Report resolved a single field.
+type ResolvedValue struct {
+	// EnvKey is the key the value was read from, or the field's env key when
+	// it was unset. Collected fields report their prefix followed by "*".
+	EnvKey string
+	// Value is the raw value before parsing, masked for fields tagged with
+	// secret. It is empty for unset and collected fields.
+	Value string
+	// Origin is where the value came from.
+	Origin Origin
+	// Err is the error for the field, or nil if it loaded and passed its
+	// constraints.
+	Err error
+}
+
+// Report loads envConfig like LoadWithOptions and also returns, for each
+// tagged field, the env key used, the raw value, and where it came from,
+// keyed by field path (e.g. "DB.Port"). Like LoadAll it keeps going past
+// invalid fields, records each field's error in the report, and returns
+// every error joined.
+//
+//	report, err := simpleenv.Report(&cfg)
+//	for field, resolved := range report {
+//		fmt.Printf("%s <- %s=%q (%s)\n", field, resolved.EnvKey, resolved.Value, resolved.Origin)
+//	}
+func Report(envConfig any, opts ...Option) (map[string]ResolvedValue, error) {
+	l := newLoader(opts)
+	l.collectErrors = true
+	l.report = map[string]ResolvedValue{}
+
+	err := l.load(envConfig)
+	return l.report, err
+}
+
+func (l *loader) recordField(fieldType reflect.StructField, fieldTag envTag, resolved ResolvedValue, err error) {
+	if fieldTag.secret {
+		resolved.Value = redactValue(resolved.Value, fieldTag.secretReveal)
+	}
+
+	resolved.Err = err
+	l.report[fieldType.Name] = resolved
+}
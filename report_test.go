@@ -0,0 +1,103 @@
+package simpleenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	type db struct {
+		Host string `env:"HOST"`
+	}
+
+	type cfg struct {
+		Port     int               `env:"SIMPLEENV_TEST_REPORT_PORT;alias=SIMPLEENV_TEST_REPORT_HTTP_PORT"`
+		Mode     string            `env:"SIMPLEENV_TEST_REPORT_MODE;default=dev"`
+		Token    string            `env:"SIMPLEENV_TEST_REPORT_TOKEN;secret=2"`
+		Password string            `env:"SIMPLEENV_TEST_REPORT_PASSWORD;secret"`
+		Debug    bool              `env:"SIMPLEENV_TEST_REPORT_DEBUG;optional"`
+		Retries  int               `env:"SIMPLEENV_TEST_REPORT_RETRIES;min=1"`
+		Labels   map[string]string `env:"SIMPLEENV_TEST_REPORT_LABEL_;collect"`
+		DB       db                `env:";prefix=SIMPLEENV_TEST_REPORT_DB_"`
+	}
+
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("hunter2\n"), 0o600); err != nil {
+		t.Fatalf("failed to write secret file: %v", err)
+	}
+
+	source := MapSource{
+		"SIMPLEENV_TEST_REPORT_HTTP_PORT":     "8080",
+		"SIMPLEENV_TEST_REPORT_TOKEN":         "abcdefgh",
+		"SIMPLEENV_TEST_REPORT_PASSWORD_FILE": passwordFile,
+		"SIMPLEENV_TEST_REPORT_RETRIES":       "0",
+		"SIMPLEENV_TEST_REPORT_LABEL_TEAM":    "payments",
+		"SIMPLEENV_TEST_REPORT_DB_HOST":       "db.local",
+	}
+
+	var c cfg
+	report, err := Report(&c, WithSource(source), WithSecretFiles())
+	if !errors.Is(err, ErrConstraint) {
+		t.Fatalf("expected the Retries constraint error, got %v", err)
+	}
+
+	want := map[string]ResolvedValue{
+		"Port":     {EnvKey: "SIMPLEENV_TEST_REPORT_HTTP_PORT", Value: "8080", Origin: OriginAlias},
+		"Mode":     {EnvKey: "SIMPLEENV_TEST_REPORT_MODE", Value: "dev", Origin: OriginDefault},
+		"Token":    {EnvKey: "SIMPLEENV_TEST_REPORT_TOKEN", Value: "ab****gh", Origin: OriginSource},
+		"Password": {EnvKey: "SIMPLEENV_TEST_REPORT_PASSWORD", Value: "****", Origin: OriginSecretFile},
+		"Debug":    {EnvKey: "SIMPLEENV_TEST_REPORT_DEBUG", Origin: OriginUnset},
+		"Retries":  {EnvKey: "SIMPLEENV_TEST_REPORT_RETRIES", Value: "0", Origin: OriginSource},
+		"Labels":   {EnvKey: "SIMPLEENV_TEST_REPORT_LABEL_*", Origin: OriginSource},
+		"DB.Host":  {EnvKey: "SIMPLEENV_TEST_REPORT_DB_HOST", Value: "db.local", Origin: OriginSource},
+	}
+
+	if len(report) != len(want) {
+		t.Fatalf("expected %d fields in report, got %d: %+v", len(want), len(report), report)
+	}
+	for field, wantValue := range want {
+		got, ok := report[field]
+		if !ok {
+			t.Fatalf("expected report entry for %s", field)
+		}
+
+		gotErr := got.Err
+		got.Err = nil
+		if !reflect.DeepEqual(got, wantValue) {
+			t.Fatalf("unexpected report entry for %s:\ngot:  %+v\nwant: %+v", field, got, wantValue)
+		}
+		if (field == "Retries") != (gotErr != nil) {
+			t.Fatalf("unexpected error for %s: %v", field, gotErr)
+		}
+	}
+
+	if c.Port != 8080 || c.Password != "hunter2" || c.DB.Host != "db.local" {
+		t.Fatalf("expected Report to load the config, got %+v", c)
+	}
+}
+
+func TestReportMissingRequired(t *testing.T) {
+	type cfg struct {
+		Host string `env:"SIMPLEENV_TEST_REPORT_MISSING"`
+	}
+
+	report, err := Report(&cfg{}, WithSource(MapSource{}))
+	if !errors.Is(err, ErrMissingRequired) {
+		t.Fatalf("expected missing value error, got %v", err)
+	}
+
+	resolved := report["Host"]
+	if resolved.Origin != OriginUnset || !errors.Is(resolved.Err, ErrMissingRequired) {
+		t.Fatalf("expected unset entry with missing error, got %+v", resolved)
+	}
+}
+
+func TestReportInvalidInput(t *testing.T) {
+	_, err := Report(nil)
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected errors.Is(err, ErrInvalidInput), got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package simpleenv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Schema holds the parsed tags of a config struct type, so it can be loaded
+// repeatedly without re-parsing tags or re-walking the type. Create one with
+// Compile; a Schema is safe for concurrent use.
+type Schema[T any] struct {
+	options options
+	fields  []schemaField
+}
+
+type schemaField struct {
+	index     []int
+	fieldType reflect.StructField
+	tag       envTag
+	// eqIndex is the index of the field named by eq=, or nil.
+	eqIndex []int
+}
+
+// Compile parses the struct tags of T once and returns a reusable Schema.
+// Tag errors are reported here rather than on every load. The options apply
+// to every Schema.Load call.
+//
+//	schema, err := simpleenv.Compile[AppEnv]()
+//	...
+//	var cfg AppEnv
+//	err = schema.Load(&cfg)
+func Compile[T any](opts ...Option) (*Schema[T], error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, loadInputError(fmt.Sprintf("a struct type, got %s", t))
+	}
+
+	l := newLoader(opts)
+	s := &Schema[T]{options: l.options}
+	err := s.compileStruct(t, nil, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Schema[T]) compileStruct(t reflect.Type, index []int, fieldPath, keyPrefix string) error {
+	for i := range t.NumField() {
+		fieldType := t.Field(i)
+		if !isFieldVisible(fieldType) {
+			continue
+		}
+		fieldType.Name = fieldPath + fieldType.Name
+		fieldIndex := append(index[:len(index):len(index)], i)
+
+		fieldTag, err := parseEnvTag(fieldType, s.options.tagName, s.options.tagSeparator)
+		if err != nil {
+			return err
+		}
+		if fieldTag.nested {
+			err = s.compileStruct(indirectType(fieldType.Type), fieldIndex, nestedFieldPath(fieldType), keyPrefix+fieldTag.prefix)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+		if !fieldTag.hasTag {
+			continue
+		}
+
+		fieldTag.addKeyPrefix(keyPrefix)
+		s.options.applyDefaults(&fieldTag)
+		field := schemaField{index: fieldIndex, fieldType: fieldType, tag: fieldTag}
+		if fieldTag.eqField != "" {
+			targetIndex, err := lookupFieldRef(t, fieldType, fieldTag)
+			if err != nil {
+				return err
+			}
+
+			field.eqIndex = append(index[:len(index):len(index)], targetIndex...)
+		}
+
+		s.fields = append(s.fields, field)
+	}
+
+	return nil
+}
+
+// Load loads env vars into envConfig using the precomputed schema. It
+// behaves like LoadWithOptions with the options given to Compile.
+func (s *Schema[T]) Load(envConfig *T) error {
+	if envConfig == nil {
+		return loadInputError(fmt.Sprintf("a non-nil pointer to a struct, got nil %T", envConfig))
+	}
+
+	l := &loader{options: s.options}
+	err := s.load(l, envConfig)
+	if err == nil && l.logger != nil {
+		l.logConfig()
+	}
+
+	return l.formatError(err)
+}
+
+func (s *Schema[T]) load(l *loader, envConfig *T) error {
+	e := reflect.ValueOf(envConfig).Elem()
+	for _, field := range s.fields {
+		_, err := l.loadTaggedField(field.fieldType, fieldByIndex(e, field.index), field.tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, field := range s.fields {
+		if field.eqIndex == nil {
+			continue
+		}
+
+		err := checkFieldMatch(fieldMatch{fieldType: field.fieldType, fieldValue: fieldByIndex(e, field.index), fieldTag: field.tag, target: fieldByIndex(e, field.eqIndex)})
+		if err != nil {
+			return err
+		}
+	}
+
+	err := l.checkUnknownKeys()
+	if err != nil {
+		return err
+	}
+
+	return runValidator(envConfig)
+}
@@ -0,0 +1,98 @@
+package simpleenv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type schemaDBConfig struct {
+	Host string `env:"HOST"`
+	Port int    `env:"PORT;min=1;max=65535"`
+}
+
+type schemaConfig struct {
+	Name    string         `env:"SIMPLEENV_TEST_SCHEMA_NAME"`
+	Timeout time.Duration  `env:"SIMPLEENV_TEST_SCHEMA_TIMEOUT;default=5s"`
+	Tags    []string       `env:"SIMPLEENV_TEST_SCHEMA_TAGS;optional"`
+	DB      schemaDBConfig `env:";prefix=SIMPLEENV_TEST_SCHEMA_DB_"`
+	note    string
+}
+
+func TestCompileLoad(t *testing.T) {
+	schema, err := Compile[schemaConfig]()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	t.Setenv("SIMPLEENV_TEST_SCHEMA_NAME", "api")
+	unsetEnv(t, "SIMPLEENV_TEST_SCHEMA_TIMEOUT")
+	t.Setenv("SIMPLEENV_TEST_SCHEMA_TAGS", "a,b")
+	t.Setenv("SIMPLEENV_TEST_SCHEMA_DB_HOST", "db.local")
+	t.Setenv("SIMPLEENV_TEST_SCHEMA_DB_PORT", "5432")
+
+	var got schemaConfig
+	if err := schema.Load(&got); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var want schemaConfig
+	if err := Load(&want); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected schema load to match Load:\ngot:  %+v\nwant: %+v", got, want)
+	}
+
+	t.Setenv("SIMPLEENV_TEST_SCHEMA_DB_PORT", "0")
+	var invalid schemaConfig
+	err = schema.Load(&invalid)
+	if !errors.Is(err, ErrConstraint) || !strings.Contains(err.Error(), `field "DB.Port"`) {
+		t.Fatalf("expected nested constraint error, got %v", err)
+	}
+}
+
+func TestCompileOptions(t *testing.T) {
+	type cfg struct {
+		Port int `cfg:"PORT;min=1"`
+	}
+
+	schema, err := Compile[cfg](WithTagName("cfg"), WithSource(MapSource{"PORT": "8080"}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for range 2 {
+		var c cfg
+		if err := schema.Load(&c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Port != 8080 {
+			t.Fatalf("expected Port 8080, got %d", c.Port)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	type badTag struct {
+		Port int `env:"PORT;allowempty"`
+	}
+
+	if _, err := Compile[badTag](); !errors.Is(err, ErrInvalidTag) {
+		t.Fatalf("expected errors.Is(err, ErrInvalidTag), got %v", err)
+	}
+
+	if _, err := Compile[int](); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected errors.Is(err, ErrInvalidInput), got %v", err)
+	}
+
+	schema, err := Compile[schemaConfig]()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := schema.Load(nil); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected errors.Is(err, ErrInvalidInput), got %v", err)
+	}
+}
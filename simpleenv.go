@@ -3,10 +3,20 @@
 package simpleenv
 
 import (
+	"cmp"
+	"context"
 	"encoding"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
+	"math"
+	"math/big"
 	"net"
+	"net/mail"
+	"net/netip"
 	"net/url"
 	"os"
 	"reflect"
@@ -14,7 +24,9 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -22,24 +34,61 @@ type envTag struct {
 	key        string
 	options    []string
 	optional   bool
+	required   bool
 	allowEmpty bool
 	trimSpace  bool
+	ignoreCase bool
+	schemes    []string
+	urlSchemes bool
+	vPrefix    bool
+	json       bool
+	char       bool
+	size       bool
+	percent    bool
+	eqField    string
+	deprecated string
+	base64     bool
+	separator  string
+	layout     string
 	hasTag     bool
+	nested     bool
+	prefix     string
+
+	secret       bool
+	secretReveal int
+
+	defaultValue string
+	hasDefault   bool
+
+	aliases    []string
+	collect    bool
+	transforms []func(string) string
+	trimSet    string
+}
+
+// elementTag returns the tag used to parse and format each element of a
+// slice or array field: the field's key and layout without its other options.
+func (t envTag) elementTag() envTag {
+	return envTag{key: t.key, options: []string{t.key}, separator: t.separator, layout: t.layout}
+}
+
+// addKeyPrefix prepends a nested struct prefix to the key and its aliases.
+func (t *envTag) addKeyPrefix(prefix string) {
+	t.key = prefix + t.key
+	for i, alias := range t.aliases {
+		t.aliases[i] = prefix + alias
+	}
 }
 
 var (
 	timeDurationType    = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	netIPType           = reflect.TypeOf(net.IP{})
+	netipAddrType       = reflect.TypeOf(netip.Addr{})
+	urlType             = reflect.TypeOf(url.URL{})
 	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
 )
 
-func fieldConstraintError(fieldName, envKey, envValue, expected string) error {
-	return fmt.Errorf("invalid value for field %q from ENV[%q]: got %q, expected %s", fieldName, envKey, envValue, expected)
-}
-
-func loadInputError(expected string) error {
-	return fmt.Errorf("invalid Load input: expected %s", expected)
-}
-
 // Load loads environment variables into the given struct
 // and validates the constraints specified in the struct tags
 //
@@ -47,29 +96,90 @@ func loadInputError(expected string) error {
 //	will load the environment variable `ENVIRONMENT` and validate
 //	that it is one of the values in the `oneof` constraint.
 //
+//	the env key may be omitted (e.g. `env:";min=1"`), in which case it is derived
+//	from the field name in SCREAMING_SNAKE_CASE (e.g. MaxConns -> MAX_CONNS).
+//
 //	valid constraints:
-//	- optional: the environment variable may be missing
-//	- allowempty: only for string or text unmarshaler fields; allows KEY="" when present
-//	- trimspace: only for string or text unmarshaler fields; trims leading/trailing whitespace before validation/parsing
-//	- oneof: the environment variable must be one of the values in the `oneof` constraint list (separeted by commas)
-//	- minlen: only for string or text unmarshaler fields; the value length must be greater than or equal to the given value
-//	- maxlen: only for string or text unmarshaler fields; the value length must be less than or equal to the given value
+//	- optional: the environment variable may be missing or empty
+//	- required: the environment variable must be set (the default); cannot be combined with optional
+//	- collect: only for map[string]string fields; the env key is a prefix and every variable starting
+//	  with it is collected, keyed by the rest of its name (e.g. `env:"LABEL_;collect"`)
+//	- alias: comma-separated fallback keys tried in order when the env key is unset (e.g. `alias=HTTP_PORT,SERVICE_PORT`)
+//	- transform: comma-separated names of functions registered with RegisterTransform; they rewrite
+//	  the value in order before validation and parsing (e.g. `transform=lower`)
+//	- deprecated: a message reported as a warning (see WithWarningHandler) when the field's key, or
+//	  one of its aliases if it has any, is read, without failing (e.g. `deprecated=use APP_PORT instead`)
+//	- default: value to use when the environment variable is missing; it is
+//	  validated and parsed like an env value (e.g. `default=8080`)
+//	- allowempty: only for string, slice, or text unmarshaler fields; allows KEY="" when present
+//	- trimspace: only for string, slice, or text unmarshaler fields; trims leading/trailing whitespace before validation/parsing
+//	- trim: like trimspace, but strips the given characters instead at both ends (e.g. `trim=/`);
+//	  it runs after trimspace
+//	- sep: only for slice and array fields; sets the element separator (defaults to ",")
+//	- layout: only for time.Time fields and lists of them; sets the time.Parse layout (defaults to time.RFC3339)
+//	- json: decodes the value with json.Unmarshal, for struct, map, and slice fields (e.g. `env:"FLAGS;json"`)
+//	- size: only for integer fields; parses sizes such as 512KB, 10MB, or 2GiB into a byte count
+//	  (e.g. `env:"MAX_UPLOAD;size;max=1GiB"`); min/max bounds may use the same suffixes
+//	- percent: only for float fields; parses 75% as 0.75 and plain fractions such as 0.75 as-is. Values must be
+//	  between 0 and 1 unless min/max/gt/gte/lt/lte bounds are given, which may be written as 50% or 0.5
+//	- char: only for rune (int32) and byte (uint8) fields; the value must be a single character, which is
+//	  stored as its code point instead of being parsed as a number (e.g. `env:"DELIM;char"` with DELIM=|)
+//	- notempty: the value must not be empty or whitespace-only
+//	- oneof: the environment variable must be one of the values in the `oneof` constraint list (separeted by commas;
+//	  escape a comma inside a value as \\, in the struct tag, e.g. `env:"MODE;oneof=a\\,b,c"`)
+//	  oneof=$NAME reads the comma-separated list from the env var NAME instead; Validate skips it
+//	- ignorecase: only with oneof; matches the oneof values case-insensitively
+//	- minlen: only for string, slice, or text unmarshaler fields; the value length (the element count for
+//	  slices) must be greater than or equal to the given value
+//	- maxlen: only for string, slice, or text unmarshaler fields; the value length (the element count for
+//	  slices) must be less than or equal to the given value
 //	- min: the environment variable must be greater than or equal to the value in the `min` constraint
 //	- max: the environment variable must be less than or equal to the value in the `max` constraint
+//	- gt, gte, lt, lte: the environment variable must be greater than, greater than or equal to,
+//	  less than, or less than or equal to the given value (min and max are aliases of gte and lte)
+//	- multipleof: only for integer fields; the value must be a multiple of the given positive integer
+//	- prefix, suffix: only for string or text unmarshaler fields; the value must start or end with
+//	  one of the comma-separated values (on nested structs, prefix prepends to env keys instead)
+//	- contains: only for string or text unmarshaler fields; the value must contain the given substring
 //	- regex: the environment variable must match the regex pattern in the `regex` constraint
+//	- validate: comma-separated names of functions registered with RegisterValidator; each is called
+//	  with the value (e.g. `validate=port-free`)
+//	- eq: the loaded value must equal the value of the named sibling field, which must have the same
+//	  type (e.g. `env:"PASSWORD_CONFIRM;eq=Password"`); it is checked once every field is loaded
 //	- format: the environment variable must match the format in the `format` constraint
-//	  supported formats: URL, URI, FILE, DIR, HOSTPORT, UUID, IP, HEX, ALPHANUMERIC, IDENTIFIER
+//	  supported formats: URL, URI, FILE, DIR, HOSTPORT, UUID, IP, IPV4, IPV6, HEX, ALPHANUMERIC, IDENTIFIER, EMAIL, SEMVER, JSON, BASE64
 //	  note: only one format value is supported (e.g. `format=URL`)
+//	- schemes: only with format=URL or on url.URL fields; comma-separated list of allowed URL schemes
+//	  (defaults to http,https)
+//	- vprefix: only with format=SEMVER; allows a leading "v" (e.g. v1.2.3)
+//	- format=BASE64 on a []byte field assigns the decoded bytes (other []byte fields get the raw value)
+//	- secret: marks the value as sensitive so Redacted masks it; secret=N keeps N characters at each end
 //
 //	supported field types:
 //	- string
 //	- bool
-//	- int
-//	- int64
-//	- uint
-//	- float64
+//	- int, int8, int16, int32, int64 (decimal, or 0x, 0o, and 0b prefixed literals)
+//	- uint, uint8, uint16, uint32, uint64 (decimal, or 0x, 0o, and 0b prefixed literals)
+//	- float32, float64
+//	- complex64, complex128 (for example: 1+2i)
 //	- time.Duration
+//	- time.Time (parsed with the layout option)
+//	- net.IP and netip.Addr (e.g. 10.0.0.1 or ::1)
+//	- url.URL, usually as *url.URL (parsed with url.Parse; a scheme or host is required)
+//	- []string (comma-separated by default, elements are trimmed)
+//	- slices of the other types above, such as []int, []time.Duration, or []*url.URL
+//	  (split like []string and parsed element by element)
+//	- fixed-size arrays such as [3]float64 (the number of values must match the length)
+//	- []byte (the raw value, or the decoded bytes with format=BASE64)
 //	- custom types implementing encoding.TextUnmarshaler
+//	- interface types with constructors registered with RegisterKind
+//	- map[string]string tagged with collect
+//	- structs, maps, and slices of them tagged with json (decoded with json.Unmarshal)
+//	- pointers to any of the above (left nil when an optional env var is missing)
+//
+//	untagged struct fields are loaded recursively, and errors for their fields
+//	report the full path (e.g. "DB.Host"). A nested struct tagged with
+//	`env:";prefix=DB_"` prepends DB_ to the env keys of its fields.
 //
 //	example:
 //		type AppEnv struct {
@@ -87,345 +197,2098 @@ func loadInputError(expected string) error {
 // Make sure to pass a non-nil pointer to a struct (for example: &cfg),
 // otherwise Load returns an input validation error.
 // Load also returns an error if required environment variables are not set
-// or if any value does not match the constraints.
+// or if any value does not match the constraints; use LoadAll to report
+// every invalid field at once.
 func Load(envConfig any) error {
+	return LoadWithOptions(envConfig)
+}
+
+// LoadWithOptions works like Load, configured by the given options.
+//
+//	err := simpleenv.LoadWithOptions(&cfg, simpleenv.WithSource(mySource))
+func LoadWithOptions(envConfig any, opts ...Option) error {
+	l := newLoader(opts)
+	return l.load(envConfig)
+}
+
+// LoadContext works like LoadWithOptions, but passes ctx to a Source that
+// implements ContextSource, so lookups against a remote store can be
+// cancelled or time out. Other sources ignore ctx.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	err := simpleenv.LoadContext(ctx, &cfg, simpleenv.WithSource(vaultSource))
+func LoadContext(ctx context.Context, envConfig any, opts ...Option) error {
+	l := newLoader(opts)
+	l.ctx = ctx
+	return l.load(envConfig)
+}
+
+// LoadFrom works like Load, but reads values from source instead of the
+// process environment. It is useful for tests and layered configs.
+func LoadFrom(envConfig any, source map[string]string) error {
+	return LoadWithOptions(envConfig, WithSource(MapSource(source)))
+}
+
+// LoadAs allocates a T, loads it like Load and returns it.
+// Go does not allow a generic Load next to the existing one, hence the name.
+//
+//	cfg, err := simpleenv.LoadAs[AppEnv]()
+func LoadAs[T any]() (*T, error) {
+	envConfig := new(T)
+	if err := Load(envConfig); err != nil {
+		return nil, err
+	}
+
+	return envConfig, nil
+}
+
+// MustLoad works like Load but panics if loading fails.
+// It is meant for main() and init() functions.
+func MustLoad(envConfig any) {
+	if err := Load(envConfig); err != nil {
+		panic(err)
+	}
+}
+
+// MustLoadAs works like LoadAs but panics if loading fails.
+func MustLoadAs[T any]() *T {
+	envConfig, err := LoadAs[T]()
+	if err != nil {
+		panic(err)
+	}
+
+	return envConfig
+}
+
+// LoadAll works like Load, but instead of stopping at the first invalid
+// field it keeps going and returns every field error joined with errors.Join,
+// one per line.
+func LoadAll(envConfig any, opts ...Option) error {
+	l := newLoader(opts)
+	l.collectErrors = true
+	return l.load(envConfig)
+}
+
+// LoadOrDefault works like LoadWithOptions, but a required env var that is
+// unset is not fatal: the field keeps its current value (the zero value for a
+// fresh struct) and the missing-required error is returned as a warning
+// instead. Values that are set are still parsed and checked, and the first
+// invalid one is returned as err, as with Load. Other warnings, such as
+// deprecated keys being read, are returned in warnings too.
+//
+// Unlike LoadAll, which collects every error but still fails, LoadOrDefault
+// lets a service start with partial config:
+//
+//	warnings, err := simpleenv.LoadOrDefault(&cfg)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, warning := range warnings {
+//		log.Printf("config: %v", warning)
+//	}
+func LoadOrDefault(envConfig any, opts ...Option) (warnings []error, err error) {
+	l := newLoader(opts)
+	l.missingAsWarning = true
+	err = l.load(envConfig)
+
+	return l.warnings, err
+}
+
+// WithWarningHandler calls handler with every non-fatal problem found while
+// loading, such as a field tagged with deprecated= being read from the
+// environment. Warnings never make Load fail.
+//
+//	simpleenv.LoadWithOptions(&cfg, simpleenv.WithWarningHandler(func(warning error) {
+//		log.Printf("config: %v", warning)
+//	}))
+func WithWarningHandler(handler func(warning error)) Option {
+	return func(o *options) {
+		o.warningHandler = handler
+	}
+}
+
+// WithErrorFormatter renders the errors Load returns with formatter, which
+// receives every field error (one for Load, all of them for LoadAll):
+//
+//	err := simpleenv.LoadAll(&cfg, simpleenv.WithErrorFormatter(func(errs []*simpleenv.FieldError) string {
+//		lines := make([]string, len(errs))
+//		for i, fieldErr := range errs {
+//			lines[i] = fmt.Sprintf("  %s: %s", fieldErr.EnvKey, fieldErr.Expected)
+//		}
+//		return "invalid config:\n" + strings.Join(lines, "\n")
+//	}))
+//
+// Only the message changes: the error still matches errors.Is and errors.As
+// as before. Errors that are not all field errors, such as invalid tags or an
+// error from Validator, keep their message.
+func WithErrorFormatter(formatter func(errs []*FieldError) string) Option {
+	return func(o *options) {
+		o.errorFormatter = formatter
+	}
+}
+
+// warn records a warning for LoadOrDefault and passes it to the handler set
+// with WithWarningHandler.
+func (l *loader) warn(warning error) {
+	l.warnings = append(l.warnings, warning)
+	if l.warningHandler != nil {
+		l.warningHandler(warning)
+	}
+}
+
+// Option configures LoadWithOptions.
+type Option func(*options)
+
+type options struct {
+	tagName      string
+	tagSeparator string
+	source       Source
+	secretFiles  bool
+	expandVars   bool
+	strictExpand bool
+	fileOverride bool
+	trimSpace    bool
+	pollInterval time.Duration
+
+	optionalByDefault bool
+	caseInsensitive   bool
+	strictSecretFiles bool
+	warningHandler    func(warning error)
+	errorFormatter    func(errs []*FieldError) string
+	logger            *slog.Logger
+	emptyAsUnset      bool
+
+	expectedPrefixes []string
+}
+
+// WithSource sets the Source values are read from (defaults to OsSource).
+func WithSource(source Source) Option {
+	return func(o *options) {
+		o.source = source
+	}
+}
+
+// WithTagName sets the struct tag key read by the loader (defaults to "env"),
+// so simpleenv can coexist with other libraries that own the env tag.
+func WithTagName(tagName string) Option {
+	return func(o *options) {
+		o.tagName = tagName
+	}
+}
+
+// WithTagSeparator sets the separator between the env key and the tag
+// options (defaults to ';'), e.g. WithTagSeparator(',') reads
+// `env:"PORT,optional,min=1"` as used by other env libraries.
+func WithTagSeparator(sep rune) Option {
+	return func(o *options) {
+		o.tagSeparator = string(sep)
+	}
+}
+
+// WithSecretFiles enables the Docker/Kubernetes secrets convention: when KEY
+// is unset but KEY_FILE is set, the contents of the file at that path
+// (trimmed of surrounding whitespace) are used as the value of KEY. If that
+// file does not exist, optional fields stay at their zero value and fields
+// with a default use it. When both KEY and KEY_FILE are set, KEY wins; use
+// WithStrictSecretFiles to reject that instead.
+func WithSecretFiles() Option {
+	return func(o *options) {
+		o.secretFiles = true
+	}
+}
+
+// WithStrictSecretFiles works like WithSecretFiles, but a key set both
+// inline and through KEY_FILE returns an error naming both keys instead of
+// using the inline value, since that is usually a deployment mistake.
+func WithStrictSecretFiles() Option {
+	return func(o *options) {
+		o.secretFiles = true
+		o.strictSecretFiles = true
+	}
+}
+
+// WithExpandVars expands ${NAME} and $NAME references in values (including
+// defaults) before validation, resolving them through the configured Source.
+// Undefined references expand to an empty string, and $$ is a literal $.
+func WithExpandVars() Option {
+	return func(o *options) {
+		o.expandVars = true
+	}
+}
+
+// WithStrictExpandVars works like WithExpandVars, but an undefined reference
+// returns an error instead of expanding to an empty string.
+func WithStrictExpandVars() Option {
+	return func(o *options) {
+		o.expandVars = true
+		o.strictExpand = true
+	}
+}
+
+// WithTrimSpace trims leading and trailing whitespace from every value before
+// validation and parsing, as if each field were tagged with trimspace. Unlike
+// the tag, it also applies to numeric, boolean, and duration fields.
+func WithTrimSpace() Option {
+	return func(o *options) {
+		o.trimSpace = true
+	}
+}
+
+// WithOptionalByDefault makes every field optional unless it is tagged with
+// required. A missing env var then leaves the field at its current value
+// instead of returning ErrMissingRequired.
+func WithOptionalByDefault() Option {
+	return func(o *options) {
+		o.optionalByDefault = true
+	}
+}
+
+// WithEmptyAsUnset treats a key that is set to an empty string (KEY=) as if
+// it were not set at all, for platforms where the two cannot be told apart.
+// An empty required field then returns ErrMissingRequired, an empty optional
+// field keeps its value, and a field with a default gets the default.
+// Aliases and KEY_FILE are tried as for a missing key, and allowempty no
+// longer lets an empty value through. It applies to every lookup, including
+// variable expansion and collect.
+func WithEmptyAsUnset() Option {
+	return func(o *options) {
+		o.emptyAsUnset = true
+	}
+}
+
+// WithCaseInsensitiveKeys retries a key that is not found as-is with a
+// case-insensitive match against the keys of the Source, for platforms and
+// shells that change the case of variable names. It requires a Source that
+// implements KeySource. Exact matches are still tried first, and a key that
+// matches several keys ignoring case is an error.
+func WithCaseInsensitiveKeys() Option {
+	return func(o *options) {
+		o.caseInsensitive = true
+	}
+}
+
+// applyDefaults applies option-level defaults to a parsed field tag.
+func (o *options) applyDefaults(fieldTag *envTag) {
+	if o.optionalByDefault && !fieldTag.required {
+		fieldTag.optional = true
+	}
+}
+
+// loader holds the state of a single Load call.
+type loader struct {
+	options
+	collectErrors bool
+	errs          []error
+	knownKeys     map[string]bool
+	report        map[string]ResolvedValue
+	// ctx is passed to a ContextSource; nil means context.Background().
+	ctx context.Context
+
+	// foldedKeys maps lowercased source keys to the keys themselves; it is
+	// built on first use by lookupSource.
+	foldedKeys map[string][]string
+
+	// matches are the eq= fields to compare once every field is loaded.
+	matches []fieldMatch
+
+	// missingAsWarning records missing required fields in warnings instead
+	// of failing; see LoadOrDefault.
+	missingAsWarning bool
+	warnings         []error
+
+	// loggedFields are the fields to log with WithSlog once loading succeeds.
+	loggedFields []loggedField
+}
+
+func newLoader(opts []Option) *loader {
+	l := &loader{options: options{tagName: "env", tagSeparator: ";", source: OsSource{}}}
+	for _, opt := range opts {
+		opt(&l.options)
+	}
+
+	return l
+}
+
+func (l *loader) load(envConfig any) error {
+	err := l.loadConfig(envConfig)
+	if err == nil {
+		err = errors.Join(l.errs...)
+	}
+	if err == nil && l.logger != nil {
+		l.logConfig()
+	}
+
+	return l.formatError(err)
+}
+
+// loadConfig loads envConfig, returning the first error. With collectErrors
+// set, field errors are appended to l.errs instead.
+func (l *loader) loadConfig(envConfig any) error {
+	e, err := structElem(envConfig)
+	if err != nil {
+		return err
+	}
+
+	err = l.loadStruct(e, "", "")
+	if err != nil {
+		return err
+	}
+
+	err = l.checkFieldMatches()
+	if err != nil {
+		return err
+	}
+
+	err = l.checkUnknownKeys()
+	if err != nil && !l.collectErrors {
+		return err
+	}
+	if err != nil {
+		l.errs = append(l.errs, err)
+	}
+	if len(l.errs) > 0 {
+		return nil
+	}
+
+	return runValidator(envConfig)
+}
+
+// Validator is implemented by config structs that need checks tag
+// constraints can't express, such as comparing two fields. Load calls
+// Validate once, after every field has been loaded and validated, and
+// returns its error unchanged.
+type Validator interface {
+	Validate() error
+}
+
+func runValidator(envConfig any) error {
+	if validator, ok := envConfig.(Validator); ok {
+		return validator.Validate()
+	}
+
+	return nil
+}
+
+// structElem returns the struct envConfig points to, or an input error when
+// envConfig is not a non-nil pointer to a struct.
+func structElem(envConfig any) (reflect.Value, error) {
 	v := reflect.ValueOf(envConfig)
 	if !v.IsValid() {
-		return loadInputError("a non-nil pointer to a struct")
+		return reflect.Value{}, loadInputError("a non-nil pointer to a struct")
 	}
 
 	if v.Kind() == reflect.Struct {
-		return loadInputError("a non-nil pointer to a struct (pass &cfg so it can be updated)")
+		return reflect.Value{}, loadInputError("a non-nil pointer to a struct (pass &cfg so it can be updated)")
 	}
 
-	if v.Kind() != reflect.Pointer || v.IsNil() {
-		return loadInputError("a non-nil pointer to a struct")
+	if v.Kind() != reflect.Pointer {
+		return reflect.Value{}, loadInputError(fmt.Sprintf("a non-nil pointer to a struct, got %s", v.Type()))
+	}
+
+	if v.IsNil() {
+		return reflect.Value{}, loadInputError(fmt.Sprintf("a non-nil pointer to a struct, got nil %s", v.Type()))
 	}
 
 	e := v.Elem()
 	if e.Kind() != reflect.Struct {
-		return loadInputError("a non-nil pointer to a struct")
+		return reflect.Value{}, loadInputError(fmt.Sprintf("a non-nil pointer to a struct, got %s", v.Type()))
 	}
 
-	t := e.Type()
+	return e, nil
+}
+
+// loadStruct loads every tagged exported field of structValue, recursing into
+// nested structs. fieldPath prefixes field names in errors (e.g. "DB.") and
+// keyPrefix prefixes env keys (e.g. "DB_").
+func (l *loader) loadStruct(structValue reflect.Value, fieldPath, keyPrefix string) error {
+	t := structValue.Type()
 
 	for i := range t.NumField() {
 		fieldType := t.Field(i)
-		fieldValue := e.Field(i)
+		if !isFieldVisible(fieldType) {
+			continue
+		}
+		fieldType.Name = fieldPath + fieldType.Name
 
-		fieldTag, err := parseEnvTag(fieldType)
-		if err != nil {
+		err := l.loadField(structValue, fieldType, structValue.Field(i), keyPrefix)
+		if err == nil {
+			continue
+		}
+
+		if !l.collectErrors {
 			return err
 		}
-		if !fieldTag.hasTag {
-			continue
+
+		l.errs = append(l.errs, err)
+	}
+
+	return nil
+}
+
+func (l *loader) loadField(structValue reflect.Value, fieldType reflect.StructField, fieldValue reflect.Value, keyPrefix string) error {
+	fieldTag, err := parseEnvTag(fieldType, l.tagName, l.tagSeparator)
+	if err != nil {
+		return err
+	}
+	if fieldTag.nested {
+		return l.loadStruct(nestedStruct(fieldValue, true), nestedFieldPath(fieldType), keyPrefix+fieldTag.prefix)
+	}
+	if !fieldTag.hasTag {
+		return nil
+	}
+
+	fieldTag.addKeyPrefix(keyPrefix)
+	l.applyDefaults(&fieldTag)
+
+	if fieldTag.eqField == "" {
+		_, err = l.loadTaggedField(fieldType, fieldValue, fieldTag)
+		return err
+	}
+
+	targetIndex, err := lookupFieldRef(structValue.Type(), fieldType, fieldTag)
+	if err != nil {
+		return err
+	}
+
+	origin, err := l.loadTaggedField(fieldType, fieldValue, fieldTag)
+	if err == nil && origin != OriginUnset {
+		l.matches = append(l.matches, fieldMatch{fieldType: fieldType, fieldValue: fieldValue, fieldTag: fieldTag, target: fieldByIndex(structValue, targetIndex)})
+	}
+
+	return err
+}
+
+// loadTaggedField loads a single non-nested field whose tag has already been
+// parsed and whose key includes any nested prefix, and returns where its
+// value came from.
+func (l *loader) loadTaggedField(fieldType reflect.StructField, fieldValue reflect.Value, fieldTag envTag) (Origin, error) {
+	resolved, err := l.resolveTaggedField(fieldType, fieldValue, fieldTag)
+	if l.report != nil {
+		l.recordField(fieldType, fieldTag, resolved, err)
+	}
+	if l.logger != nil {
+		l.recordLogField(fieldType.Name, fieldTag, resolved)
+	}
+	if fieldTag.deprecated != "" && isDeprecatedRead(fieldTag, resolved.Origin) {
+		l.warn(newKindError(ErrDeprecatedKey, "field %q reads deprecated ENV[%q]: %s", fieldType.Name, resolved.EnvKey, fieldTag.deprecated))
+	}
+	if l.missingAsWarning && errors.Is(err, ErrMissingRequired) {
+		l.warn(err)
+		return resolved.Origin, nil
+	}
+
+	return resolved.Origin, err
+}
+
+// isDeprecatedRead reports whether a value read from origin used a deprecated
+// key. On a field with aliases the aliases are the deprecated keys, so the
+// field can be renamed with `env:"NEW;alias=OLD;deprecated=..."`; otherwise
+// the field's own key is.
+func isDeprecatedRead(fieldTag envTag, origin Origin) bool {
+	if len(fieldTag.aliases) > 0 {
+		return origin == OriginAlias
+	}
+
+	return origin == OriginSource || origin == OriginSecretFile
+}
+
+// resolveTaggedField loads a field and describes where its value came from.
+func (l *loader) resolveTaggedField(fieldType reflect.StructField, fieldValue reflect.Value, fieldTag envTag) (ResolvedValue, error) {
+	if fieldTag.collect {
+		err := l.loadCollectedField(fieldType, fieldValue, fieldTag)
+		resolved := ResolvedValue{EnvKey: fieldTag.key + "*", Origin: OriginUnset}
+		if fieldValue.Len() > 0 {
+			resolved.Origin = OriginSource
 		}
 
-		envValue, found := os.LookupEnv(fieldTag.key)
-		if !found {
-			if fieldTag.optional {
-				continue
+		return resolved, err
+	}
+
+	key, envValue, origin, err := l.lookupKeys(fieldType, fieldTag)
+	if err != nil {
+		return ResolvedValue{EnvKey: fieldTag.key, Origin: OriginUnset}, err
+	}
+	if origin == OriginUnset {
+		if fieldTag.hasDefault {
+			resolved := ResolvedValue{EnvKey: fieldTag.key, Value: fieldTag.defaultValue, Origin: OriginDefault}
+			err = redactFieldError(l.loadFieldValue(fieldType, fieldValue, fieldTag, fieldTag.defaultValue), fieldTag)
+			if err != nil {
+				return resolved, fmt.Errorf("invalid default for field %q (ENV[%q]): %w", fieldType.Name, fieldTag.key, err)
 			}
 
-			return fieldConstraintError(fieldType.Name, fieldTag.key, "<unset>", "a value to set or to be marked as optional")
+			return resolved, nil
 		}
 
-		normalizedValue := envValue
-		if fieldTag.trimSpace {
-			normalizedValue = strings.TrimSpace(envValue)
+		resolved := ResolvedValue{EnvKey: fieldTag.key, Origin: OriginUnset}
+		if fieldTag.optional {
+			return resolved, nil
 		}
 
-		if normalizedValue == "" && !fieldTag.allowEmpty {
-			return fieldConstraintError(fieldType.Name, fieldTag.key, normalizedValue, "a non-empty value")
+		return resolved, fieldMissingError(fieldType.Name, fieldTag.key)
+	}
+
+	fieldTag.key = key
+	resolved := ResolvedValue{EnvKey: key, Value: envValue, Origin: origin}
+	return resolved, redactFieldError(l.loadFieldValue(fieldType, fieldValue, fieldTag, envValue), fieldTag)
+}
+
+// loadCollectedField gathers every key starting with fieldTag.key into a
+// map keyed by the rest of the key, e.g. LABEL_TEAM=payments becomes
+// {"TEAM": "payments"} for `env:"LABEL_;collect"`.
+func (l *loader) loadCollectedField(fieldType reflect.StructField, fieldValue reflect.Value, fieldTag envTag) error {
+	keySource, ok := l.source.(KeySource)
+	if !ok {
+		return fmt.Errorf("field %q (ENV[%q]) uses collect, which requires a Source that implements KeySource", fieldType.Name, fieldTag.key)
+	}
+
+	collected := map[string]string{}
+	for _, key := range keySource.Keys() {
+		mapKey, ok := strings.CutPrefix(key, fieldTag.key)
+		if !ok || mapKey == "" {
+			continue
 		}
+		l.markKnown(key)
 
-		err = validateConstraints(fieldType, fieldTag.options, normalizedValue)
+		envValue, found, err := l.lookup(fieldType, key)
 		if err != nil {
 			return err
 		}
+		if !found {
+			continue
+		}
 
-		parsedValue, err := parseValueFromEnv(fieldType, fieldTag.key, normalizedValue)
+		keyTag := fieldTag
+		keyTag.key = key
+		envValue, err = l.expandValue(fieldType, keyTag, envValue)
 		if err != nil {
-			return err
+			return redactFieldError(err, fieldTag)
+		}
+		if l.trimSpace {
+			envValue = strings.TrimSpace(envValue)
+		}
+
+		collected[mapKey] = envValue
+	}
+
+	if len(collected) == 0 {
+		if fieldTag.optional {
+			return nil
+		}
+
+		return fieldMissingError(fieldType.Name, fieldTag.key+"*")
+	}
+
+	// Build the map entry by entry, since named key or value types (map[Label]Value)
+	// cannot be converted from map[string]string as a whole.
+	mapType := fieldValue.Type()
+	mapValue := reflect.MakeMapWithSize(mapType, len(collected))
+	for key, value := range collected {
+		mapValue.SetMapIndex(reflect.ValueOf(key).Convert(mapType.Key()), reflect.ValueOf(value).Convert(mapType.Elem()))
+	}
+
+	fieldValue.Set(mapValue)
+	return nil
+}
+
+// lookupKeys tries fieldTag.key and then each alias in order, returning the
+// first key that is set along with its value and origin. The origin is
+// OriginUnset when no key is set.
+func (l *loader) lookupKeys(fieldType reflect.StructField, fieldTag envTag) (key, envValue string, origin Origin, err error) {
+	keys := append([]string{fieldTag.key}, fieldTag.aliases...)
+	for _, key := range keys {
+		l.markKnown(key)
+		if l.secretFiles {
+			l.markKnown(key + "_FILE")
+		}
+	}
+
+	for _, key := range keys {
+		matched, envValue, origin, err := l.lookupValue(fieldType, fieldTag, key)
+		if origin == OriginSource && key != fieldTag.key {
+			origin = OriginAlias
+		}
+		if err != nil || origin != OriginUnset {
+			return matched, envValue, origin, err
+		}
+	}
+
+	return "", "", OriginUnset, nil
+}
+
+// lookupValue reads the raw value for key from the source, falling back to
+// the KEY_FILE convention when secret files are enabled. A KEY_FILE pointing
+// at a file that does not exist counts as unset for optional fields and
+// fields with a default. It also returns the key that was read, which only
+// differs from key with WithCaseInsensitiveKeys.
+func (l *loader) lookupValue(fieldType reflect.StructField, fieldTag envTag, key string) (string, string, Origin, error) {
+	matched, envValue, found, err := l.lookupSource(fieldType, key)
+	if err != nil || (found && !l.strictSecretFiles) {
+		return matched, envValue, OriginSource, err
+	}
+	if !l.secretFiles {
+		return key, "", OriginUnset, nil
+	}
+
+	fileKey, path, fileFound, err := l.lookupSource(fieldType, key+"_FILE")
+	if err != nil {
+		return key, "", OriginUnset, err
+	}
+	if found && fileFound {
+		return key, "", OriginUnset, fmt.Errorf("field %q is set by both ENV[%q] and ENV[%q]; unset one of them", fieldType.Name, matched, fileKey)
+	}
+	if found {
+		return matched, envValue, OriginSource, nil
+	}
+	if !fileFound {
+		return key, "", OriginUnset, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && (fieldTag.optional || fieldTag.hasDefault) {
+		return key, "", OriginUnset, nil
+	}
+	if err != nil {
+		return key, "", OriginUnset, fmt.Errorf("failed to read field %q from ENV[%q]: %w", fieldType.Name, fileKey, err)
+	}
+
+	return key, strings.TrimSpace(string(content)), OriginSecretFile, nil
+}
+
+// lookupSource reads key from the source. With WithCaseInsensitiveKeys, a
+// key that is not found as-is is matched against the source keys ignoring
+// case, and the key that matched is returned.
+func (l *loader) lookupSource(fieldType reflect.StructField, key string) (string, string, bool, error) {
+	envValue, found, err := l.lookup(fieldType, key)
+	if err != nil || found || !l.caseInsensitive {
+		return key, envValue, found, err
+	}
+
+	if l.foldedKeys == nil {
+		keySource, ok := l.source.(KeySource)
+		if !ok {
+			return key, "", false, fmt.Errorf("field %q (ENV[%q]) is looked up with WithCaseInsensitiveKeys, which requires a Source that implements KeySource", fieldType.Name, key)
+		}
+
+		l.foldedKeys = map[string][]string{}
+		for _, sourceKey := range keySource.Keys() {
+			folded := strings.ToLower(sourceKey)
+			if !slices.Contains(l.foldedKeys[folded], sourceKey) {
+				l.foldedKeys[folded] = append(l.foldedKeys[folded], sourceKey)
+			}
 		}
+	}
+
+	matches := l.foldedKeys[strings.ToLower(key)]
+	switch len(matches) {
+	case 0:
+		return key, "", false, nil
+	case 1:
+		l.markKnown(matches[0])
+		envValue, found, err = l.lookup(fieldType, matches[0])
+		return matches[0], envValue, found, err
+	}
+
+	matches = slices.Sorted(slices.Values(matches))
+	return key, "", false, fmt.Errorf("field %q (ENV[%q]) matches several keys ignoring case: %s", fieldType.Name, key, quoteList(matches))
+}
+
+// lookup reads key from the source, passing the load context to a
+// ContextSource.
+func (l *loader) lookup(fieldType reflect.StructField, key string) (string, bool, error) {
+	ctx := l.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	envValue, found, err := lookupContext(ctx, l.source, key)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up field %q from ENV[%q]: %w", fieldType.Name, key, err)
+	}
+	if found && envValue == "" && l.emptyAsUnset {
+		return "", false, nil
+	}
+
+	return envValue, found, nil
+}
+
+// expandValue resolves ${NAME} and $NAME references when expansion is enabled.
+// $$ is kept as a literal $, both in envValue and in the referenced values.
+func (l *loader) expandValue(fieldType reflect.StructField, fieldTag envTag, envValue string) (string, error) {
+	if !l.expandVars {
+		return envValue, nil
+	}
+
+	var undefined []string
+	var lookupErr error
+	parts := strings.Split(envValue, "$$")
+	for i, part := range parts {
+		parts[i] = os.Expand(part, func(name string) string {
+			l.markKnown(name)
+			value, found, err := l.lookup(fieldType, name)
+			if err != nil && lookupErr == nil {
+				lookupErr = err
+			}
+			if !found {
+				undefined = append(undefined, name)
+			}
+
+			return unescapeDollars(value)
+		})
+	}
+	expanded := strings.Join(parts, "$")
+
+	if lookupErr != nil {
+		return "", lookupErr
+	}
+
+	if l.strictExpand && len(undefined) > 0 {
+		return "", fieldConstraintError(fieldType.Name, fieldTag.key, envValue, "", fmt.Sprintf("referenced variable %q to be set", undefined[0]))
+	}
+
+	return expanded, nil
+}
+
+// resolveOneOfRef replaces a oneof=$NAME constraint with the values listed,
+// comma-separated, in the env var NAME. Each value is trimmed.
+func (l *loader) resolveOneOfRef(fieldType reflect.StructField, fieldTag envTag) (envTag, error) {
+	for i, option := range fieldTag.options {
+		name, ok := oneOfRef(option)
+		if i == 0 || !ok {
+			continue
+		}
+
+		l.markKnown(name)
+		list, found, err := l.lookup(fieldType, name)
+		if err != nil {
+			return fieldTag, err
+		}
+		if l.expandVars {
+			list = unescapeDollars(list)
+		}
+		if !found {
+			// Not ErrMissingRequired: LoadOrDefault would turn it into a warning and
+			// skip the oneof check for a value that is actually set.
+			return fieldTag, newKindError(ErrConstraint, "field %q (ENV[%q]) reads its oneof values from ENV[%q], which is not set", fieldType.Name, fieldTag.key, name)
+		}
+
+		values := splitOptionList(list)
+		for j, value := range values {
+			values[j] = strings.ReplaceAll(strings.TrimSpace(value), ",", `\,`)
+		}
+
+		fieldTag.options = slices.Clone(fieldTag.options)
+		fieldTag.options[i] = "oneof=" + strings.Join(values, ",")
+	}
+
+	return fieldTag, nil
+}
+
+// unescapeDollars turns the $$ that stands for a literal $ under expansion
+// back into $, for values that are used without being expanded themselves.
+func unescapeDollars(value string) string {
+	return strings.ReplaceAll(value, "$$", "$")
+}
+
+// oneOfRef returns NAME for a oneof=$NAME or oneof=${NAME} option.
+func oneOfRef(option string) (string, bool) {
+	ref, ok := strings.CutPrefix(option, "oneof=$")
+	if !ok {
+		return "", false
+	}
+	if inner, braced := strings.CutPrefix(ref, "{"); braced {
+		ref = strings.TrimSuffix(inner, "}")
+	}
+
+	return ref, true
+}
+
+// loadFieldValue validates and parses a raw value and assigns it to fieldValue.
+func (l *loader) loadFieldValue(fieldType reflect.StructField, fieldValue reflect.Value, fieldTag envTag, envValue string) error {
+	normalizedValue, err := l.expandValue(fieldType, fieldTag, envValue)
+	if err != nil {
+		return err
+	}
+
+	if fieldTag.trimSpace || l.trimSpace {
+		normalizedValue = strings.TrimSpace(normalizedValue)
+	}
+	if fieldTag.trimSet != "" {
+		normalizedValue = strings.Trim(normalizedValue, fieldTag.trimSet)
+	}
+	normalizedValue = applyTransforms(fieldTag.transforms, normalizedValue)
+
+	if normalizedValue == "" && !fieldTag.allowEmpty {
+		// An optional field treats KEY= like a missing key, unless notempty
+		// explicitly asks for a value.
+		if fieldTag.optional && !slices.Contains(fieldTag.options[1:], "notempty") {
+			return nil
+		}
+
+		return fieldConstraintError(fieldType.Name, fieldTag.key, normalizedValue, "", "a non-empty value")
+	}
+
+	fieldTag, err = l.resolveOneOfRef(fieldType, fieldTag)
+	if err != nil {
+		return err
+	}
+
+	err = validateConstraints(fieldType, fieldTag, normalizedValue)
+	if err != nil {
+		return err
+	}
+
+	parsedValue, err := parseValueFromEnv(fieldType, fieldTag, normalizedValue)
+	if err != nil {
+		return err
+	}
+
+	err = assignFieldValue(fieldValue, parsedValue)
+	if err != nil {
+		return fmt.Errorf("failed to assign field %q from ENV[%q]: %w", fieldType.Name, fieldTag.key, err)
+	}
+
+	return nil
+}
+
+func parseEnvTag(fieldType reflect.StructField, tagName, tagSeparator string) (envTag, error) {
+	tagValue, hasEnvTag := fieldType.Tag.Lookup(tagName)
+	if !hasEnvTag {
+		if strings.Contains(string(fieldType.Tag), tagName+":") {
+			return envTag{}, tagError(fieldType.Name, "", "malformed %s tag, expected %s:\"ENV_KEY%s...\"", tagName, tagName, tagSeparator)
+		}
+
+		return envTag{hasTag: false, nested: isNestedStruct(fieldType)}, nil
+	}
+
+	if strings.TrimSpace(tagValue) == "" {
+		return envTag{}, tagError(fieldType.Name, "", "env key cannot be empty")
+	}
+
+	tagOptions := splitTagOptions(tagValue, tagSeparator)
+
+	if tagOptions[0] == "" && isNestedStruct(fieldType) {
+		return parseNestedTag(fieldType, tagOptions)
+	}
+
+	if tagOptions[0] == "" {
+		tagOptions[0] = deriveEnvKey(fieldType.Name)
+	}
+
+	envKey := tagOptions[0]
+	if _, ok := lookupTagOption(tagOptions, "prefix="); ok && !isStringLike(fieldType.Type) {
+		return envTag{}, tagError(fieldType.Name, envKey, "prefix is only supported for nested struct fields and string or encoding.TextUnmarshaler types")
+	}
+	if _, ok := lookupTagOption(tagOptions, "suffix="); ok && !isStringLike(fieldType.Type) {
+		return envTag{}, tagError(fieldType.Name, envKey, "suffix is only supported for string or encoding.TextUnmarshaler types")
+	}
+	if substr, ok := lookupTagOption(tagOptions, "contains="); ok {
+		if !isStringLike(fieldType.Type) {
+			return envTag{}, tagError(fieldType.Name, envKey, "contains is only supported for string or encoding.TextUnmarshaler types")
+		}
+		if substr == "" {
+			return envTag{}, tagError(fieldType.Name, envKey, "contains cannot be empty")
+		}
+	}
+
+	for _, option := range tagOptions[1:] {
+		if !isTagModifier(option) && !isConstraint(option) {
+			return envTag{}, tagError(fieldType.Name, envKey, "unsupported constraint %q", option)
+		}
+	}
+
+	optional := slices.Contains(tagOptions, "optional")
+	required := slices.Contains(tagOptions, "required")
+	if optional && required {
+		return envTag{}, tagError(fieldType.Name, envKey, "optional and required cannot be used together")
+	}
+
+	collect := slices.Contains(tagOptions, "collect")
+	if collect {
+		if !isStringMap(fieldType.Type) {
+			return envTag{}, tagError(fieldType.Name, envKey, "collect is only supported for map[string]string types")
+		}
+
+		for _, option := range tagOptions[1:] {
+			switch option {
+			case "", "collect", "optional", "required", "secret":
+			default:
+				return envTag{}, tagError(fieldType.Name, envKey, "collect cannot be combined with %q", option)
+			}
+		}
+	}
+
+	allowEmpty := slices.Contains(tagOptions, "allowempty")
+	trimSpace := slices.Contains(tagOptions, "trimspace")
+	ignoreCase := slices.Contains(tagOptions, "ignorecase")
+	if allowEmpty && !supportsAllowEmpty(fieldType.Type) {
+		return envTag{}, tagError(fieldType.Name, envKey, "allowempty is only supported for string, slice, or encoding.TextUnmarshaler types")
+	}
+
+	if allowEmpty && slices.Contains(tagOptions, "notempty") {
+		return envTag{}, tagError(fieldType.Name, envKey, "allowempty and notempty cannot be used together")
+	}
+
+	if trimSpace && !supportsTrimSpace(fieldType.Type) {
+		return envTag{}, tagError(fieldType.Name, envKey, "trimspace is only supported for string, slice, or encoding.TextUnmarshaler types")
+	}
+
+	trimSet, hasTrimSet := lookupTagOption(tagOptions, "trim=")
+	trimSet = normalizeQuotedValue(trimSet)
+	if hasTrimSet && trimSet == "" {
+		return envTag{}, tagError(fieldType.Name, envKey, "trim cannot be empty")
+	}
+	if hasTrimSet && !supportsTrimSpace(fieldType.Type) {
+		return envTag{}, tagError(fieldType.Name, envKey, "trim is only supported for string, slice, or encoding.TextUnmarshaler types")
+	}
+
+	oneOf, hasOneOf := lookupTagOption(tagOptions, "oneof=")
+	if name, ok := oneOfRef("oneof=" + oneOf); hasOneOf && ok && !isDotenvKey(name) {
+		return envTag{}, tagError(fieldType.Name, envKey, "oneof=$ must name an env var, got %q", name)
+	}
+	if ignoreCase && !hasOneOf {
+		return envTag{}, tagError(fieldType.Name, envKey, "ignorecase requires a oneof constraint")
+	}
+
+	schemes := defaultURLSchemes
+	urlSchemes := false
+	if schemesValue, ok := lookupTagOption(tagOptions, "schemes="); ok {
+		format, _ := lookupTagOption(tagOptions, "format=")
+		urlSchemes = !strings.EqualFold(strings.TrimSpace(format), "URL")
+		if urlSchemes && indirectType(fieldType.Type) != urlType {
+			return envTag{}, tagError(fieldType.Name, envKey, "schemes requires format=URL or a url.URL field")
+		}
+		if strings.TrimSpace(schemesValue) == "" {
+			return envTag{}, tagError(fieldType.Name, envKey, "schemes cannot be empty")
+		}
+
+		schemes = nil
+		for _, scheme := range strings.Split(schemesValue, ",") {
+			schemes = append(schemes, strings.TrimSpace(scheme))
+		}
+	}
+
+	vPrefix := slices.Contains(tagOptions, "vprefix")
+	if format, _ := lookupTagOption(tagOptions, "format="); vPrefix && !strings.EqualFold(strings.TrimSpace(format), "SEMVER") {
+		return envTag{}, tagError(fieldType.Name, envKey, "vprefix requires format=SEMVER")
+	}
+
+	jsonValue := slices.Contains(tagOptions, "json")
+	format, _ := lookupTagOption(tagOptions, "format=")
+	base64Value := strings.EqualFold(strings.TrimSpace(format), "BASE64")
+
+	char := slices.Contains(tagOptions, "char")
+	if char {
+		if kind := indirectType(fieldType.Type).Kind(); kind != reflect.Int32 && kind != reflect.Uint8 {
+			return envTag{}, tagError(fieldType.Name, envKey, "char is only supported for rune (int32) and byte (uint8) types")
+		}
+		if jsonValue {
+			return envTag{}, tagError(fieldType.Name, envKey, "char cannot be used together with json")
+		}
+		if hasNumericConstraint(tagOptions) {
+			return envTag{}, tagError(fieldType.Name, envKey, "char cannot be combined with numeric constraints (min, max, gt, gte, lt, lte, multipleof)")
+		}
+	}
+
+	separator := ","
+	if sep, ok := lookupTagOption(tagOptions, "sep="); ok {
+		if !isList(fieldType.Type) {
+			return envTag{}, tagError(fieldType.Name, envKey, "sep is only supported for slice and array types")
+		}
+		if jsonValue {
+			return envTag{}, tagError(fieldType.Name, envKey, "sep cannot be used together with json")
+		}
+		if sep == "" {
+			return envTag{}, tagError(fieldType.Name, envKey, "sep cannot be empty")
+		}
+
+		separator = sep
+	}
+
+	layout := time.RFC3339
+	if layoutValue, ok := lookupTagOption(tagOptions, "layout="); ok {
+		if valueType := indirectType(fieldType.Type); valueType != timeType && !(isList(valueType) && valueType.Elem() == timeType) {
+			return envTag{}, tagError(fieldType.Name, envKey, "layout is only supported for time.Time types and lists of them")
+		}
+		if layoutValue == "" {
+			return envTag{}, tagError(fieldType.Name, envKey, "layout cannot be empty")
+		}
+
+		layout = layoutValue
+	}
+
+	size := slices.Contains(tagOptions, "size")
+	if size {
+		if valueType := indirectType(fieldType.Type); valueType == timeDurationType || !isIntegerKind(valueType.Kind()) {
+			return envTag{}, tagError(fieldType.Name, envKey, "size is only supported for integer types")
+		}
+		if char || jsonValue {
+			return envTag{}, tagError(fieldType.Name, envKey, "size cannot be combined with char or json")
+		}
+	}
+
+	percent := slices.Contains(tagOptions, "percent")
+	if percent {
+		if kind := indirectType(fieldType.Type).Kind(); kind != reflect.Float32 && kind != reflect.Float64 {
+			return envTag{}, tagError(fieldType.Name, envKey, "percent is only supported for float types")
+		}
+		if jsonValue {
+			return envTag{}, tagError(fieldType.Name, envKey, "percent cannot be used together with json")
+		}
+	}
+
+	if err := validateBoundSyntax(fieldType, envKey, tagOptions, size, percent); err != nil {
+		return envTag{}, err
+	}
+
+	for _, option := range tagOptions {
+		if pattern, ok := strings.CutPrefix(option, "regex="); ok {
+			if _, err := compileRegex(normalizeQuotedValue(pattern)); err != nil {
+				return envTag{}, tagError(fieldType.Name, envKey, "regex=%s is not a valid pattern: %v", pattern, err)
+			}
+		}
+	}
+
+	if _, ok := lookupTagOption(tagOptions, "multipleof="); ok && !isIntegerKind(indirectType(fieldType.Type).Kind()) {
+		return envTag{}, tagError(fieldType.Name, envKey, "multipleof is only supported for integer types")
+	}
+
+	if hasLengthConstraint(tagOptions) && !supportsStringLength(fieldType.Type) {
+		return envTag{}, tagError(fieldType.Name, envKey, "minlen/maxlen are only supported for string, slice, or encoding.TextUnmarshaler types")
+	}
+	if hasLengthConstraint(tagOptions) && jsonValue && isList(fieldType.Type) {
+		return envTag{}, tagError(fieldType.Name, envKey, "minlen/maxlen cannot be used together with json on slices")
+	}
+
+	secret := slices.Contains(tagOptions, "secret")
+	secretReveal := 0
+	if revealValue, ok := lookupTagOption(tagOptions, "secret="); ok {
+		reveal, err := strconv.Atoi(revealValue)
+		if err != nil || reveal < 0 {
+			return envTag{}, tagError(fieldType.Name, envKey, "secret must be a non-negative integer, got %q", revealValue)
+		}
+
+		secret = true
+		secretReveal = reveal
+	}
+
+	var aliases []string
+	if aliasValue, ok := lookupTagOption(tagOptions, "alias="); ok {
+		for _, alias := range strings.Split(aliasValue, ",") {
+			alias = strings.TrimSpace(alias)
+			if alias == "" {
+				return envTag{}, tagError(fieldType.Name, envKey, "alias cannot contain empty keys, got %q", aliasValue)
+			}
+
+			aliases = append(aliases, alias)
+		}
+	}
+
+	for _, option := range tagOptions[1:] {
+		if !strings.HasPrefix(option, "validate=") {
+			continue
+		}
+
+		for _, name := range validatorNames(option) {
+			if _, ok := lookupValidator(name); !ok {
+				return envTag{}, tagError(fieldType.Name, envKey, "unknown validator %q (register it with RegisterValidator)", name)
+			}
+		}
+	}
+
+	var transforms []func(string) string
+	if transformValue, ok := lookupTagOption(tagOptions, "transform="); ok {
+		fns, err := parseTransforms(transformValue)
+		if err != nil {
+			return envTag{}, tagError(fieldType.Name, envKey, "%v", err)
+		}
+
+		transforms = fns
+	}
+
+	eqField, ok := lookupTagOption(tagOptions, "eq=")
+	if ok && eqField == "" {
+		return envTag{}, tagError(fieldType.Name, envKey, "eq must name a field")
+	}
+
+	deprecated, ok := lookupTagOption(tagOptions, "deprecated=")
+	if ok && strings.TrimSpace(deprecated) == "" {
+		return envTag{}, tagError(fieldType.Name, envKey, "deprecated needs a message, for example deprecated=use APP_PORT instead")
+	}
+
+	defaultValue, hasDefault := lookupTagOption(tagOptions, "default=")
+
+	return envTag{
+		key:        envKey,
+		options:    tagOptions,
+		optional:   optional,
+		required:   required,
+		allowEmpty: allowEmpty,
+		trimSpace:  trimSpace,
+		ignoreCase: ignoreCase,
+		schemes:    schemes,
+		urlSchemes: urlSchemes,
+		vPrefix:    vPrefix,
+		json:       jsonValue,
+		char:       char,
+		size:       size,
+		percent:    percent,
+		eqField:    eqField,
+		deprecated: deprecated,
+		base64:     base64Value,
+		separator:  separator,
+		layout:     layout,
+		hasTag:     true,
+
+		secret:       secret,
+		secretReveal: secretReveal,
+
+		defaultValue: defaultValue,
+		hasDefault:   hasDefault,
+
+		aliases:    aliases,
+		collect:    collect,
+		transforms: transforms,
+		trimSet:    trimSet,
+	}, nil
+}
+
+// deriveEnvKey converts a Go field name to SCREAMING_SNAKE_CASE, e.g.
+// "APIBaseURL" -> "API_BASE_URL". For nested fields only the last path
+// segment is used.
+func deriveEnvKey(fieldName string) string {
+	if i := strings.LastIndex(fieldName, "."); i >= 0 {
+		fieldName = fieldName[i+1:]
+	}
+
+	runes := []rune(fieldName)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteByte('_')
+			}
+		}
+
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	return b.String()
+}
+
+// splitTagOptions splits a tag value into the env key and trimmed options.
+// With a separator other than ';', a part that is not an option continues
+// the value of the option before it, so `VERSION,regex=^v[0-9]{1,3}$,optional`
+// keeps the pattern together. After a list-valued option (see isListOption), any
+// part without '=' continues the list even if it names a modifier, so
+// `FORMAT,oneof=text,json` keeps json as an allowed value; only a name=value
+// part starts a new option there.
+func splitTagOptions(tagValue, tagSeparator string) []string {
+	parts := strings.Split(tagValue, tagSeparator)
+	tagOptions := make([]string, 0, len(parts))
+	for i, part := range parts {
+		option := strings.TrimSpace(part)
+		if tagSeparator != ";" && i > 1 && continuesOption(tagOptions[len(tagOptions)-1], option) {
+			tagOptions[len(tagOptions)-1] = strings.TrimSpace(tagOptions[len(tagOptions)-1] + tagSeparator + part)
+			continue
+		}
+
+		tagOptions = append(tagOptions, option)
+	}
+
+	return tagOptions
+}
+
+// continuesOption reports whether part belongs to the value of the option
+// before it rather than starting a new option.
+func continuesOption(previous, part string) bool {
+	if !strings.Contains(previous, "=") {
+		return false
+	}
+	if isListOption(previous) && !strings.Contains(part, "=") {
+		return true
+	}
+
+	return !isTagModifier(part) && !isConstraint(part)
+}
+
+// isListOption reports whether option takes a comma-separated list (or, for
+// default=, a value that may itself be a list).
+func isListOption(option string) bool {
+	for _, prefix := range []string{"oneof=", "alias=", "prefix=", "suffix=", "schemes=", "validate=", "transform=", "default="} {
+		if strings.HasPrefix(option, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseNestedTag parses a nested struct tag such as env:";prefix=DB_".
+func parseNestedTag(fieldType reflect.StructField, tagOptions []string) (envTag, error) {
+	prefix := ""
+	for _, option := range tagOptions[1:] {
+		switch {
+		case option == "":
+			continue
+		case strings.HasPrefix(option, "prefix="):
+			prefix = strings.TrimPrefix(option, "prefix=")
+		default:
+			return envTag{}, tagError(fieldType.Name, "", "nested struct tags only support prefix, got %q", option)
+		}
+	}
+
+	return envTag{nested: true, prefix: prefix}, nil
+}
+
+func validateConstraints(fieldType reflect.StructField, fieldTag envTag, envValue string) error {
+	envKey := fieldTag.key
+
+	for _, constraint := range fieldTag.options[1:] {
+		if isTagModifier(constraint) {
+			continue
+		}
+
+		switch {
+		case constraint == "notempty":
+			if strings.TrimSpace(envValue) == "" {
+				return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, "a value that is not empty or whitespace-only")
+			}
+		case strings.HasPrefix(constraint, "oneof="):
+			if _, ok := oneOfRef(constraint); ok {
+				// Only resolved while loading; Validate reads no env vars.
+				continue
+			}
+
+			strOpts := strings.TrimPrefix(constraint, "oneof=")
+			opts := splitOptionList(strOpts)
+			matches := slices.Contains(opts, envValue)
+			if fieldTag.ignoreCase {
+				matches = slices.ContainsFunc(opts, func(opt string) bool {
+					return strings.EqualFold(opt, envValue)
+				})
+			}
+
+			if !matches {
+				return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("one of [%s]", strOpts))
+			}
+		case strings.HasPrefix(constraint, "minlen="):
+			minLen, err := parseLenConstraint(fieldType, envKey, constraint, "minlen=")
+			if err != nil {
+				return err
+			}
+
+			valueLen := valueLength(fieldType, fieldTag, envValue)
+			if valueLen < minLen && isList(fieldType.Type) {
+				return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a list with >= %d elements (got %d)", minLen, valueLen))
+			}
+			if valueLen < minLen {
+				return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a value with length >= %d (got length %d)", minLen, valueLen))
+			}
+		case strings.HasPrefix(constraint, "maxlen="):
+			maxLen, err := parseLenConstraint(fieldType, envKey, constraint, "maxlen=")
+			if err != nil {
+				return err
+			}
+
+			valueLen := valueLength(fieldType, fieldTag, envValue)
+			if valueLen > maxLen && isList(fieldType.Type) {
+				return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a list with <= %d elements (got %d)", maxLen, valueLen))
+			}
+			if valueLen > maxLen {
+				return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a value with length <= %d (got length %d)", maxLen, valueLen))
+			}
+		case strings.HasPrefix(constraint, "min="):
+			err := validateBound(fieldType, fieldTag, constraint, "min", envValue, ">=")
+			if err != nil {
+				return err
+			}
+		case strings.HasPrefix(constraint, "max="):
+			err := validateBound(fieldType, fieldTag, constraint, "max", envValue, "<=")
+			if err != nil {
+				return err
+			}
+		case strings.HasPrefix(constraint, "gt="):
+			err := validateBound(fieldType, fieldTag, constraint, "gt", envValue, ">")
+			if err != nil {
+				return err
+			}
+		case strings.HasPrefix(constraint, "gte="):
+			err := validateBound(fieldType, fieldTag, constraint, "gte", envValue, ">=")
+			if err != nil {
+				return err
+			}
+		case strings.HasPrefix(constraint, "lt="):
+			err := validateBound(fieldType, fieldTag, constraint, "lt", envValue, "<")
+			if err != nil {
+				return err
+			}
+		case strings.HasPrefix(constraint, "lte="):
+			err := validateBound(fieldType, fieldTag, constraint, "lte", envValue, "<=")
+			if err != nil {
+				return err
+			}
+		case strings.HasPrefix(constraint, "multipleof="):
+			step, ok := new(big.Int).SetString(strings.TrimPrefix(constraint, "multipleof="), 10)
+			if !ok || step.Sign() <= 0 {
+				return tagError(fieldType.Name, envKey, "%q must be a positive integer", constraint)
+			}
+
+			var value *big.Int
+			if fieldTag.size {
+				value, ok = parseSize(envValue)
+			} else {
+				value, ok = new(big.Int).SetString(envValue, integerBase(envValue))
+			}
+			if !ok {
+				return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, "an integer value for multipleof comparison")
+			}
+
+			if new(big.Int).Rem(value, step).Sign() != 0 {
+				return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a multiple of %s", step))
+			}
+		case strings.HasPrefix(constraint, "prefix="):
+			strOpts := strings.TrimPrefix(constraint, "prefix=")
+			matches := slices.ContainsFunc(splitOptionList(strOpts), func(prefix string) bool {
+				return strings.HasPrefix(envValue, prefix)
+			})
+			if !matches {
+				return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a value starting with one of [%s]", strOpts))
+			}
+		case strings.HasPrefix(constraint, "suffix="):
+			strOpts := strings.TrimPrefix(constraint, "suffix=")
+			matches := slices.ContainsFunc(splitOptionList(strOpts), func(suffix string) bool {
+				return strings.HasSuffix(envValue, suffix)
+			})
+			if !matches {
+				return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a value ending with one of [%s]", strOpts))
+			}
+		case strings.HasPrefix(constraint, "contains="):
+			substr := strings.TrimPrefix(constraint, "contains=")
+			if !strings.Contains(envValue, substr) {
+				return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a value containing %q", substr))
+			}
+		case strings.HasPrefix(constraint, "regex="):
+			patternstr := normalizeQuotedValue(strings.TrimPrefix(constraint, "regex="))
+			matches, err := matchRegex(patternstr, envValue)
+			if err != nil {
+				return tagError(fieldType.Name, envKey, "regex=%s is not a valid pattern: %v", patternstr, err)
+			}
+			if !matches {
+				return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("to match regex %q", patternstr))
+			}
+		case strings.HasPrefix(constraint, "validate="):
+			for _, name := range validatorNames(constraint) {
+				fn, ok := lookupValidator(name)
+				if !ok {
+					return tagError(fieldType.Name, envKey, "unknown validator %q", name)
+				}
+
+				if err := fn(envValue); err != nil {
+					constraintErr := fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a value accepted by validator %q", name))
+					constraintErr.Err = err
+					return constraintErr
+				}
+			}
+		case strings.HasPrefix(constraint, "format="):
+			format := strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(constraint, "format=")))
+			if strings.Contains(format, "|") {
+				return tagError(fieldType.Name, envKey, "multiple format values are not supported, got %q", format)
+			}
+
+			expected, ok := validateFormat(format, envValue, fieldTag)
+			if expected == "" {
+				return tagError(fieldType.Name, envKey, "unsupported format %q", format)
+			}
+			if !ok {
+				return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, expected)
+			}
+		case strings.HasPrefix(constraint, "eq="):
+			// Compared with the referenced field once every field is loaded.
+		default:
+			return tagError(fieldType.Name, envKey, "unsupported constraint %q", constraint)
+		}
+	}
+
+	if fieldTag.percent && !hasBoundConstraint(fieldTag.options) {
+		if value, ok := parsePercent(envValue); ok && (value < 0 || value > 1) {
+			return fieldConstraintError(fieldType.Name, envKey, envValue, "percent", "a percentage between 0% and 100%")
+		}
+	}
+
+	if fieldTag.urlSchemes && !isValidURL(envValue, fieldTag.schemes) {
+		constraint := "schemes=" + strings.Join(fieldTag.schemes, ",")
+		return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a valid URL with %s scheme", strings.Join(fieldTag.schemes, "/")))
+	}
+
+	return nil
+}
+
+// validateBoundSyntax reports a tag error for a min/max/gt/gte/lt/lte bound
+// that cannot be parsed for the field type (durations like 1s for
+// time.Duration, numbers otherwise) or is set on a list, whose value can
+// never be compared, so the mistake surfaces even when the env var is unset.
+func validateBoundSyntax(fieldType reflect.StructField, envKey string, tagOptions []string, size, percent bool) error {
+	valueType := indirectType(fieldType.Type)
+	for _, option := range tagOptions[1:] {
+		name, bound, _ := strings.Cut(option, "=")
+		switch name {
+		case "min", "max", "gt", "gte", "lt", "lte":
+		default:
+			continue
+		}
+
+		switch {
+		case valueType.Kind() == reflect.Complex64 || valueType.Kind() == reflect.Complex128:
+			return tagError(fieldType.Name, envKey, "%s is not supported for complex types, which are unordered", name)
+		case valueType.Kind() == reflect.Slice || valueType.Kind() == reflect.Array:
+			return tagError(fieldType.Name, envKey, "%s is not supported for slice and array types; use minlen or maxlen to bound the number of elements", name)
+		case size:
+			if _, ok := parseSize(bound); !ok {
+				return tagError(fieldType.Name, envKey, "%q must be a valid size", option)
+			}
+		case percent:
+			if _, ok := parsePercent(bound); !ok {
+				return tagError(fieldType.Name, envKey, "%q must be a valid percentage", option)
+			}
+		case valueType == timeDurationType:
+			if _, err := time.ParseDuration(bound); err != nil {
+				return tagError(fieldType.Name, envKey, "%q must be a valid duration", option)
+			}
+		case isIntegerKind(valueType.Kind()):
+			if _, ok := new(big.Rat).SetString(bound); !ok {
+				return tagError(fieldType.Name, envKey, "%q must be a valid number", option)
+			}
+		default:
+			if _, err := strconv.ParseFloat(bound, 64); err != nil {
+				return tagError(fieldType.Name, envKey, "%q must be a valid number", option)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateBound checks envValue against a bound constraint such as "min=1".
+// op is the comparison the value must satisfy (">", ">=", "<", or "<="). Integer fields
+// are compared exactly, so large int64/uint64 bounds keep their precision.
+func validateBound(fieldType reflect.StructField, fieldTag envTag, constraint, name, envValue, op string) error {
+	envKey := fieldTag.key
+	bound := strings.TrimPrefix(constraint, name+"=")
+	valueType := indirectType(fieldType.Type)
+
+	var order int
+	switch {
+	case fieldTag.size:
+		boundSize, ok := parseSize(bound)
+		if !ok {
+			return tagError(fieldType.Name, envKey, "%q must be a valid size", constraint)
+		}
+
+		valueSize, ok := parseSize(envValue)
+		if !ok {
+			return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a valid size for %s comparison", name))
+		}
+
+		order = valueSize.Cmp(boundSize)
+	case fieldTag.percent:
+		boundPercent, ok := parsePercent(bound)
+		if !ok {
+			return tagError(fieldType.Name, envKey, "%q must be a valid percentage", constraint)
+		}
+
+		valuePercent, ok := parsePercent(envValue)
+		if !ok {
+			return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a valid percentage for %s comparison", name))
+		}
+
+		order = cmp.Compare(valuePercent, boundPercent)
+	case valueType == timeDurationType:
+		boundDuration, err := time.ParseDuration(bound)
+		if err != nil {
+			return tagError(fieldType.Name, envKey, "%q must be a valid duration", constraint)
+		}
+
+		valueDuration, err := time.ParseDuration(envValue)
+		if err != nil {
+			return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a valid duration for %s comparison", name))
+		}
+
+		order = cmp.Compare(valueDuration, boundDuration)
+	case isIntegerKind(valueType.Kind()):
+		boundRat, ok := new(big.Rat).SetString(bound)
+		if !ok {
+			return tagError(fieldType.Name, envKey, "%q must be a valid number", constraint)
+		}
+
+		valueInt, ok := new(big.Int).SetString(envValue, integerBase(envValue))
+		if !ok {
+			return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a numeric value for %s comparison", name))
+		}
+
+		order = new(big.Rat).SetInt(valueInt).Cmp(boundRat)
+	default:
+		boundFloat, err := strconv.ParseFloat(bound, 64)
+		if err != nil {
+			return tagError(fieldType.Name, envKey, "%q must be a valid number", constraint)
+		}
+
+		valueFloat, err := strconv.ParseFloat(envValue, 64)
+		if err != nil {
+			return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a numeric value for %s comparison", name))
+		}
+
+		order = cmp.Compare(valueFloat, boundFloat)
+	}
+
+	satisfied := false
+	switch op {
+	case ">":
+		satisfied = order > 0
+	case ">=":
+		satisfied = order >= 0
+	case "<":
+		satisfied = order < 0
+	case "<=":
+		satisfied = order <= 0
+	}
+
+	if !satisfied {
+		return fieldConstraintError(fieldType.Name, envKey, envValue, constraint, fmt.Sprintf("a value %s %s", op, bound))
+	}
+
+	return nil
+}
+
+// integerBase returns the base to parse an integer literal with: 0 (so the
+// 0x, 0o, and 0b prefixes are honored) when one of those prefixes is present,
+// and 10 otherwise, so zero-padded decimals like "010" stay decimal.
+func integerBase(value string) int {
+	digits := strings.TrimLeft(value, "+-")
+	if len(digits) < 2 || digits[0] != '0' {
+		return 10
+	}
+
+	switch digits[1] {
+	case 'x', 'X', 'o', 'O', 'b', 'B':
+		return 0
+	default:
+		return 10
+	}
+}
+
+func isIntegerKind(kind reflect.Kind) bool {
+	return (kind >= reflect.Int && kind <= reflect.Int64) || (kind >= reflect.Uint && kind <= reflect.Uint64)
+}
+
+func parseValueFromEnv(fieldType reflect.StructField, fieldTag envTag, envValue string) (reflect.Value, error) {
+	if fieldType.Type.Kind() != reflect.Pointer {
+		return parseValue(fieldType.Name, fieldType.Type, fieldTag, envValue)
+	}
+
+	elemValue, err := parseValue(fieldType.Name, fieldType.Type.Elem(), fieldTag, envValue)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	ptr := reflect.New(fieldType.Type.Elem())
+	ptr.Elem().Set(elemValue)
+	return ptr, nil
+}
+
+func parseValue(fieldName string, valueType reflect.Type, fieldTag envTag, envValue string) (reflect.Value, error) {
+	envKey := fieldTag.key
+	if fieldTag.json {
+		valuePtr := reflect.New(valueType)
+		if err := json.Unmarshal([]byte(envValue), valuePtr.Interface()); err != nil {
+			parseErr := fieldParseError(fieldName, envKey, envValue, fmt.Sprintf("valid JSON for %s", valueType))
+			parseErr.Err = err
+			return reflect.Value{}, parseErr
+		}
+
+		return valuePtr.Elem(), nil
+	}
+
+	if valueType.Kind() == reflect.Interface {
+		return parseKind(fieldName, valueType, envKey, envValue)
+	}
+
+	if fieldTag.char {
+		return parseChar(fieldName, valueType, envKey, envValue)
+	}
+
+	if fieldTag.size {
+		return parseSizeValue(fieldName, valueType, envKey, envValue)
+	}
+
+	if fieldTag.percent {
+		return parsePercentValue(fieldName, valueType, envKey, envValue)
+	}
+
+	if valueType == timeDurationType {
+		durationValue, err := time.ParseDuration(envValue)
+		if err != nil {
+			return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, "a valid time.Duration (for example: 500ms, 2s, 1m)")
+		}
+
+		return reflect.ValueOf(durationValue), nil
+	}
+
+	if valueType == timeType {
+		timeValue, err := time.Parse(fieldTag.layout, envValue)
+		if err != nil {
+			return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, fmt.Sprintf("a valid time in layout %q", fieldTag.layout))
+		}
+
+		return reflect.ValueOf(timeValue), nil
+	}
+
+	if valueType == netIPType {
+		ip := net.ParseIP(envValue)
+		if ip == nil {
+			return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, "a valid IP address (for example: 10.0.0.1 or ::1)")
+		}
+
+		return reflect.ValueOf(ip), nil
+	}
+
+	if valueType == netipAddrType {
+		addr, err := netip.ParseAddr(envValue)
+		if err != nil {
+			parseErr := fieldParseError(fieldName, envKey, envValue, "a valid IP address (for example: 10.0.0.1 or ::1)")
+			parseErr.Err = err
+			return reflect.Value{}, parseErr
+		}
+
+		return reflect.ValueOf(addr), nil
+	}
+
+	if valueType == urlType {
+		u, err := url.Parse(envValue)
+		if err != nil {
+			parseErr := fieldParseError(fieldName, envKey, envValue, "a valid URL")
+			parseErr.Err = err
+			return reflect.Value{}, parseErr
+		}
+		if u.Scheme == "" && u.Host == "" {
+			return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, "a valid URL with a scheme or host (for example: https://api.example.com)")
+		}
+
+		return reflect.ValueOf(*u), nil
+	}
+
+	if unmarshaledValue, ok, err := parseWithTextUnmarshaler(fieldName, valueType, envKey, envValue); ok || err != nil {
+		return unmarshaledValue, err
+	}
+
+	switch valueType.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(envValue), nil
+	case reflect.Bool:
+		boolValue, err := parseBool(envValue)
+		if err != nil {
+			return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, "a valid bool")
+		}
+
+		return reflect.ValueOf(boolValue), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		intValue, err := strconv.ParseInt(envValue, integerBase(envValue), valueType.Bits())
+		if err != nil {
+			return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, numericExpectation(valueType, envValue, err))
+		}
+
+		value := reflect.New(valueType).Elem()
+		value.SetInt(intValue)
+		return value, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		uintValue, err := strconv.ParseUint(envValue, integerBase(envValue), valueType.Bits())
+		if err != nil {
+			return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, numericExpectation(valueType, envValue, err))
+		}
+
+		value := reflect.New(valueType).Elem()
+		value.SetUint(uintValue)
+		return value, nil
+	case reflect.Float32, reflect.Float64:
+		floatValue, err := strconv.ParseFloat(envValue, valueType.Bits())
+		if err != nil {
+			return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, numericExpectation(valueType, envValue, err))
+		}
+
+		value := reflect.New(valueType).Elem()
+		value.SetFloat(floatValue)
+		return value, nil
+	case reflect.Complex64, reflect.Complex128:
+		complexValue, err := strconv.ParseComplex(envValue, valueType.Bits())
+		if err != nil {
+			return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, fmt.Sprintf("a valid %s (for example: 1+2i)", valueType.Kind()))
+		}
+
+		value := reflect.New(valueType).Elem()
+		value.SetComplex(complexValue)
+		return value, nil
+	case reflect.Slice:
+		if isByteSlice(valueType) {
+			return parseBytes(fieldName, valueType, fieldTag, envValue)
+		}
+		if !isStringSlice(valueType) {
+			return parseList(fieldName, valueType, fieldTag, envValue)
+		}
+
+		return parseStringSlice(valueType, envValue, fieldTag.separator), nil
+	case reflect.Array:
+		return parseList(fieldName, valueType, fieldTag, envValue)
+	default:
+		return reflect.Value{}, unsupportedTypeError(fieldName, envKey, valueType)
+	}
+}
+
+// parseChar stores a single-character value as its code point in a rune or
+// byte field. Byte fields only accept ASCII characters.
+func parseChar(fieldName string, valueType reflect.Type, envKey, envValue string) (reflect.Value, error) {
+	value := reflect.New(valueType).Elem()
+	if valueType.Kind() == reflect.Uint8 {
+		if len(envValue) != 1 || envValue[0] >= utf8.RuneSelf {
+			return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, "a single ASCII character")
+		}
+
+		value.SetUint(uint64(envValue[0]))
+		return value, nil
+	}
+
+	r, size := utf8.DecodeRuneInString(envValue)
+	if size == 0 || size != len(envValue) || (r == utf8.RuneError && size == 1) {
+		return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, "a single character")
+	}
 
-		err = assignFieldValue(fieldValue, parsedValue)
+	value.SetInt(int64(r))
+	return value, nil
+}
+
+// parseBytes assigns the raw value to a []byte field, or the decoded bytes
+// when the field uses format=BASE64.
+func parseBytes(fieldName string, valueType reflect.Type, fieldTag envTag, envValue string) (reflect.Value, error) {
+	data := []byte(envValue)
+	if fieldTag.base64 {
+		var err error
+		data, err = base64.StdEncoding.DecodeString(envValue)
 		if err != nil {
-			return fmt.Errorf("failed to assign field %q from ENV[%q]: %w", fieldType.Name, fieldTag.key, err)
+			parseErr := fieldParseError(fieldName, fieldTag.key, envValue, "valid base64")
+			parseErr.Err = err
+			return reflect.Value{}, parseErr
 		}
 	}
 
-	return nil
+	return reflect.ValueOf(data).Convert(valueType), nil
 }
 
-func parseEnvTag(fieldType reflect.StructField) (envTag, error) {
-	tagValue, hasEnvTag := fieldType.Tag.Lookup("env")
-	if !hasEnvTag {
-		if strings.Contains(string(fieldType.Tag), "env:") {
-			return envTag{}, fmt.Errorf("invalid tag for field %q: malformed env tag, expected env:\"ENV_KEY;...\"", fieldType.Name)
-		}
+// sizeUnits maps size suffixes, lowercased, to their multiplier in bytes.
+var sizeUnits = map[string]int64{
+	"": 1, "b": 1,
+	"kb": 1e3, "mb": 1e6, "gb": 1e9, "tb": 1e12, "pb": 1e15,
+	"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30, "tib": 1 << 40, "pib": 1 << 50,
+}
+
+var sizePattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([A-Za-z]*)$`)
 
-		return envTag{hasTag: false}, nil
+// parseSize converts a size such as 512KB, 10MB, 1.5GiB, or 4096 to a byte
+// count. SI suffixes (KB, MB, ...) are powers of 1000 and IEC suffixes (KiB,
+// MiB, ...) powers of 1024, in any case. Sizes that are not a whole number
+// of bytes are rejected.
+func parseSize(value string) (*big.Int, bool) {
+	match := sizePattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return nil, false
 	}
 
-	if strings.TrimSpace(tagValue) == "" {
-		return envTag{}, fmt.Errorf("invalid tag for field %q: env key cannot be empty", fieldType.Name)
+	multiplier, ok := sizeUnits[strings.ToLower(match[2])]
+	if !ok {
+		return nil, false
 	}
 
-	rawTagOptions := strings.Split(tagValue, ";")
-	tagOptions := make([]string, 0, len(rawTagOptions))
-	for _, option := range rawTagOptions {
-		tagOptions = append(tagOptions, strings.TrimSpace(option))
+	size, ok := new(big.Rat).SetString(match[1])
+	if !ok {
+		return nil, false
 	}
 
-	if len(tagOptions) < 1 || strings.TrimSpace(tagOptions[0]) == "" {
-		return envTag{}, fmt.Errorf("invalid tag for field %q: env key cannot be empty", fieldType.Name)
+	size.Mul(size, new(big.Rat).SetInt64(multiplier))
+	if !size.IsInt() {
+		return nil, false
 	}
 
-	envKey := strings.TrimSpace(tagOptions[0])
-	optional := slices.Contains(tagOptions, "optional")
-	allowEmpty := slices.Contains(tagOptions, "allowempty")
-	trimSpace := slices.Contains(tagOptions, "trimspace")
-	if allowEmpty && !supportsAllowEmpty(fieldType.Type) {
-		return envTag{}, fmt.Errorf("invalid tag for field %q (ENV[%q]): allowempty is only supported for string or encoding.TextUnmarshaler types", fieldType.Name, envKey)
+	return size.Num(), true
+}
+
+// parsePercent converts a percentage such as 75% or 12.5% to a fraction
+// (0.75, 0.125). Values without a % suffix are taken to be fractions already.
+func parsePercent(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	number, isPercent := strings.CutSuffix(value, "%")
+	number = strings.TrimSpace(number)
+
+	if f, err := strconv.ParseFloat(number, 64); err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
 	}
 
-	if trimSpace && !supportsTrimSpace(fieldType.Type) {
-		return envTag{}, fmt.Errorf("invalid tag for field %q (ENV[%q]): trimspace is only supported for string or encoding.TextUnmarshaler types", fieldType.Name, envKey)
+	fraction, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return 0, false
+	}
+	if isPercent {
+		fraction.Quo(fraction, big.NewRat(100, 1))
 	}
 
-	if hasLengthConstraint(tagOptions) && !supportsStringLength(fieldType.Type) {
-		return envTag{}, fmt.Errorf("invalid tag for field %q (ENV[%q]): minlen/maxlen are only supported for string or encoding.TextUnmarshaler types", fieldType.Name, envKey)
+	f, _ := fraction.Float64()
+	return f, true
+}
+
+func parsePercentValue(fieldName string, valueType reflect.Type, envKey, envValue string) (reflect.Value, error) {
+	fraction, ok := parsePercent(envValue)
+	if !ok {
+		return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, "a percentage such as 75% or a fraction such as 0.75")
 	}
 
-	return envTag{
-		key:        envKey,
-		options:    tagOptions,
-		optional:   optional,
-		allowEmpty: allowEmpty,
-		trimSpace:  trimSpace,
-		hasTag:     true,
-	}, nil
+	value := reflect.New(valueType).Elem()
+	value.SetFloat(fraction)
+	return value, nil
 }
 
-func validateConstraints(fieldType reflect.StructField, tagOptions []string, envValue string) error {
-	envKey := tagOptions[0]
+func parseSizeValue(fieldName string, valueType reflect.Type, envKey, envValue string) (reflect.Value, error) {
+	size, ok := parseSize(envValue)
+	if !ok {
+		return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, "a size in bytes such as 4096, 512KB, 10MB, or 2GiB")
+	}
 
-	for _, constraint := range tagOptions[1:] {
-		if constraint == "" || constraint == "optional" || constraint == "allowempty" || constraint == "trimspace" {
-			continue
+	value := reflect.New(valueType).Elem()
+	if kind := valueType.Kind(); kind >= reflect.Uint && kind <= reflect.Uint64 {
+		if !size.IsUint64() || value.OverflowUint(size.Uint64()) {
+			return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, fmt.Sprintf("a size that fits in %s", valueType.Kind()))
 		}
 
-		switch {
-		case strings.HasPrefix(constraint, "oneof="):
-			strOpts := strings.TrimPrefix(constraint, "oneof=")
-			opts := strings.Split(strOpts, ",")
-			if !slices.Contains(opts, envValue) {
-				return fieldConstraintError(fieldType.Name, envKey, envValue, fmt.Sprintf("one of [%s]", strOpts))
-			}
-		case strings.HasPrefix(constraint, "minlen="):
-			minLen, err := parseLenConstraint(fieldType, envKey, constraint, "minlen=")
-			if err != nil {
-				return err
-			}
+		value.SetUint(size.Uint64())
+		return value, nil
+	}
 
-			valueLen := utf8.RuneCountInString(envValue)
-			if valueLen < minLen {
-				return fieldConstraintError(fieldType.Name, envKey, envValue, fmt.Sprintf("a value with length >= %d", minLen))
-			}
-		case strings.HasPrefix(constraint, "maxlen="):
-			maxLen, err := parseLenConstraint(fieldType, envKey, constraint, "maxlen=")
-			if err != nil {
-				return err
-			}
+	if !size.IsInt64() || value.OverflowInt(size.Int64()) {
+		return reflect.Value{}, fieldParseError(fieldName, envKey, envValue, fmt.Sprintf("a size that fits in %s", valueType.Kind()))
+	}
 
-			valueLen := utf8.RuneCountInString(envValue)
-			if valueLen > maxLen {
-				return fieldConstraintError(fieldType.Name, envKey, envValue, fmt.Sprintf("a value with length <= %d", maxLen))
-			}
-		case strings.HasPrefix(constraint, "min="):
-			minstr := strings.TrimPrefix(constraint, "min=")
+	value.SetInt(size.Int64())
+	return value, nil
+}
 
-			if fieldType.Type == timeDurationType {
-				minDuration, err := time.ParseDuration(minstr)
-				if err != nil {
-					return fmt.Errorf("invalid tag for field %q (ENV[%q]): %q must be a valid duration", fieldType.Name, envKey, constraint)
-				}
+// parseBool accepts the strconv.ParseBool values plus yes/no, y/n, and on/off
+// in any case.
+func parseBool(value string) (bool, error) {
+	if boolValue, err := strconv.ParseBool(value); err == nil {
+		return boolValue, nil
+	}
 
-				fieldDuration, err := time.ParseDuration(envValue)
-				if err != nil {
-					return fieldConstraintError(fieldType.Name, envKey, envValue, "a valid duration for min comparison")
-				}
+	switch strings.ToLower(value) {
+	case "yes", "y", "on":
+		return true, nil
+	case "no", "n", "off":
+		return false, nil
+	default:
+		return false, strconv.ErrSyntax
+	}
+}
 
-				if fieldDuration < minDuration {
-					return fieldConstraintError(fieldType.Name, envKey, envValue, fmt.Sprintf("a value >= %s", minstr))
-				}
+// parseList splits envValue on the field separator and parses each trimmed
+// element into a slice or fixed-size array. An array must receive exactly
+// Len() values; an empty value gives an empty slice.
+func parseList(fieldName string, listType reflect.Type, fieldTag envTag, envValue string) (reflect.Value, error) {
+	elemType := listType.Elem()
+	// []*url.URL elements are parsed as url.URL and then stored by pointer.
+	parseType := elemType
+	if elemType.Kind() == reflect.Pointer && elemType.Elem() == urlType {
+		parseType = urlType
+	}
+	if kind := parseType.Kind(); parseType != netIPType && (kind == reflect.Array || kind == reflect.Slice || kind == reflect.Map || kind == reflect.Pointer) {
+		return reflect.Value{}, unsupportedTypeError(fieldName, fieldTag.key, listType)
+	}
 
-				continue
-			}
+	parts := strings.Split(envValue, fieldTag.separator)
+	var list reflect.Value
+	if listType.Kind() == reflect.Array {
+		if len(parts) != listType.Len() {
+			return reflect.Value{}, fieldParseError(fieldName, fieldTag.key, envValue, fmt.Sprintf("exactly %d values separated by %q, got %d", listType.Len(), fieldTag.separator, len(parts)))
+		}
 
-			min, err := strconv.ParseFloat(minstr, 64)
-			if err != nil {
-				return fmt.Errorf("invalid tag for field %q (ENV[%q]): %q must be a valid number", fieldType.Name, envKey, constraint)
-			}
+		list = reflect.New(listType).Elem()
+	} else {
+		if envValue == "" {
+			return reflect.Zero(listType), nil
+		}
 
-			fieldValue, err := strconv.ParseFloat(envValue, 64)
-			if err != nil {
-				return fieldConstraintError(fieldType.Name, envKey, envValue, "a numeric value for min comparison")
-			}
+		list = reflect.MakeSlice(listType, len(parts), len(parts))
+	}
 
-			if fieldValue < min {
-				return fieldConstraintError(fieldType.Name, envKey, envValue, fmt.Sprintf("a value >= %s", minstr))
+	for i, part := range parts {
+		elem, err := parseValue(fieldName, parseType, fieldTag.elementTag(), strings.TrimSpace(part))
+		if err != nil {
+			var fieldErr *FieldError
+			if errors.As(err, &fieldErr) {
+				elemErr := fieldParseError(fieldName, fieldTag.key, envValue, fmt.Sprintf("element %d to be %s", i, fieldErr.Expected))
+				elemErr.Err = fieldErr.Err
+				return reflect.Value{}, elemErr
 			}
-		case strings.HasPrefix(constraint, "max="):
-			maxstr := strings.TrimPrefix(constraint, "max=")
 
-			if fieldType.Type == timeDurationType {
-				maxDuration, err := time.ParseDuration(maxstr)
-				if err != nil {
-					return fmt.Errorf("invalid tag for field %q (ENV[%q]): %q must be a valid duration", fieldType.Name, envKey, constraint)
-				}
+			return reflect.Value{}, err
+		}
+		if parseType != elemType {
+			ptr := reflect.New(parseType)
+			ptr.Elem().Set(elem)
+			elem = ptr
+		}
 
-				fieldDuration, err := time.ParseDuration(envValue)
-				if err != nil {
-					return fieldConstraintError(fieldType.Name, envKey, envValue, "a valid duration for max comparison")
-				}
+		list.Index(i).Set(elem)
+	}
 
-				if fieldDuration > maxDuration {
-					return fieldConstraintError(fieldType.Name, envKey, envValue, fmt.Sprintf("a value <= %s", maxstr))
-				}
+	return list, nil
+}
 
-				continue
-			}
+func parseStringSlice(sliceType reflect.Type, envValue, separator string) reflect.Value {
+	if envValue == "" {
+		return reflect.Zero(sliceType)
+	}
 
-			max, err := strconv.ParseFloat(maxstr, 64)
-			if err != nil {
-				return fmt.Errorf("invalid tag for field %q (ENV[%q]): %q must be a valid number", fieldType.Name, envKey, constraint)
-			}
+	parts := strings.Split(envValue, separator)
+	slice := reflect.MakeSlice(sliceType, len(parts), len(parts))
+	for i, part := range parts {
+		slice.Index(i).SetString(strings.TrimSpace(part))
+	}
 
-			fieldValue, err := strconv.ParseFloat(envValue, 64)
-			if err != nil {
-				return fieldConstraintError(fieldType.Name, envKey, envValue, "a numeric value for max comparison")
-			}
+	return slice
+}
 
-			if fieldValue > max {
-				return fieldConstraintError(fieldType.Name, envKey, envValue, fmt.Sprintf("a value <= %s", maxstr))
-			}
-		case strings.HasPrefix(constraint, "regex="):
-			patternstr := normalizeQuotedValue(strings.TrimPrefix(constraint, "regex="))
-			_, err := matchRegex(patternstr, envValue)
-			if err != nil {
-				return fieldConstraintError(fieldType.Name, envKey, envValue, fmt.Sprintf("to match regex %q", patternstr))
-			}
-		case strings.HasPrefix(constraint, "format="):
-			format := strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(constraint, "format=")))
-			if strings.Contains(format, "|") {
-				return fmt.Errorf("invalid tag for field %q (ENV[%q]): multiple format values are not supported, got %q", fieldType.Name, envKey, format)
-			}
+func parseWithTextUnmarshaler(fieldName string, valueType reflect.Type, envKey, envValue string) (reflect.Value, bool, error) {
+	valuePtr := reflect.New(valueType)
+	if !valuePtr.Type().Implements(textUnmarshalerType) {
+		return reflect.Value{}, false, nil
+	}
 
-			expected, ok := validateFormat(format, envValue)
-			if expected == "" {
-				return fmt.Errorf("invalid tag for field %q (ENV[%q]): unsupported format %q", fieldType.Name, envKey, format)
-			}
-			if !ok {
-				return fieldConstraintError(fieldType.Name, envKey, envValue, expected)
-			}
-		default:
-			return fmt.Errorf("invalid tag for field %q (ENV[%q]): unsupported constraint %q", fieldType.Name, envKey, constraint)
-		}
+	unmarshaler := valuePtr.Interface().(encoding.TextUnmarshaler)
+	if err := unmarshaler.UnmarshalText([]byte(envValue)); err != nil {
+		parseErr := fieldParseError(fieldName, envKey, envValue, "a valid value for custom text unmarshaler")
+		parseErr.Err = err
+		return reflect.Value{}, true, parseErr
 	}
 
-	return nil
+	return valuePtr.Elem(), true, nil
 }
 
-func parseValueFromEnv(fieldType reflect.StructField, envKey, envValue string) (reflect.Value, error) {
-	if fieldType.Type == timeDurationType {
-		durationValue, err := time.ParseDuration(envValue)
-		if err != nil {
-			return reflect.Value{}, fieldConstraintError(fieldType.Name, envKey, envValue, "a valid time.Duration (for example: 500ms, 2s, 1m)")
-		}
+func numericExpectation(fieldType reflect.Type, envValue string, err error) string {
+	kind := fieldType.Kind()
+	isUnsigned := kind >= reflect.Uint && kind <= reflect.Uint64
+	if isUnsigned && strings.HasPrefix(envValue, "-") {
+		return fmt.Sprintf("a valid %s (non-negative integer)", kind)
+	}
 
-		return reflect.ValueOf(durationValue), nil
+	if !errors.Is(err, strconv.ErrRange) {
+		return fmt.Sprintf("a valid %s", kind)
 	}
 
-	if unmarshaledValue, ok, err := parseWithTextUnmarshaler(fieldType, envKey, envValue); ok || err != nil {
-		return unmarshaledValue, err
+	bits := fieldType.Bits()
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		minValue := int64(-1) << (bits - 1)
+		maxValue := int64(1)<<(bits-1) - 1
+		return fmt.Sprintf("a valid %s within range [%d, %d]", kind, minValue, maxValue)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		maxValue := ^uint64(0) >> (64 - bits)
+		return fmt.Sprintf("a valid %s within range [0, %d]", kind, maxValue)
+	default:
+		return fmt.Sprintf("a valid %s within range", kind)
 	}
+}
 
-	switch fieldType.Type.Kind() {
-	case reflect.String:
-		return reflect.ValueOf(envValue), nil
-	case reflect.Bool:
-		boolValue, err := strconv.ParseBool(envValue)
-		if err != nil {
-			return reflect.Value{}, fieldConstraintError(fieldType.Name, envKey, envValue, "a valid bool")
-		}
+// isFieldVisible reports whether a struct field is loaded: exported fields,
+// plus embedded structs, whose exported fields are promoted even when the
+// embedded type itself is unexported.
+func isFieldVisible(fieldType reflect.StructField) bool {
+	return fieldType.IsExported() || (fieldType.Anonymous && fieldType.Type.Kind() == reflect.Struct)
+}
 
-		return reflect.ValueOf(boolValue), nil
+// nestedFieldPath returns the field path prefix for the fields of a nested
+// struct, e.g. "DB." for DB. Fields of an embedded struct are promoted, so
+// they keep the path of the struct that embeds it.
+func nestedFieldPath(fieldType reflect.StructField) string {
+	if fieldType.Anonymous {
+		return fieldType.Name[:strings.LastIndex(fieldType.Name, ".")+1]
+	}
 
-	case reflect.Int:
-		intValue, err := strconv.Atoi(envValue)
-		if err != nil {
-			return reflect.Value{}, fieldConstraintError(fieldType.Name, envKey, envValue, "a valid int")
-		}
+	return fieldType.Name + "."
+}
 
-		return reflect.ValueOf(intValue), nil
-	case reflect.Int64:
-		int64Value, err := strconv.ParseInt(envValue, 10, 64)
-		if err != nil {
-			return reflect.Value{}, fieldConstraintError(fieldType.Name, envKey, envValue, "a valid int64")
-		}
+// nestedStruct returns the struct value of a nested field. A nil embedded
+// struct pointer is allocated when alloc is set; otherwise a zero struct
+// stands in for it and the field is left untouched.
+func nestedStruct(fieldValue reflect.Value, alloc bool) reflect.Value {
+	if fieldValue.Kind() != reflect.Pointer {
+		return fieldValue
+	}
 
-		return reflect.ValueOf(int64Value), nil
-	case reflect.Uint:
-		uintValue, err := strconv.ParseUint(envValue, 10, strconv.IntSize)
-		if err != nil {
-			return reflect.Value{}, fieldConstraintError(fieldType.Name, envKey, envValue, "a valid uint")
+	if fieldValue.IsNil() {
+		if !alloc {
+			return reflect.New(fieldValue.Type().Elem()).Elem()
 		}
 
-		return reflect.ValueOf(uint(uintValue)), nil
-	case reflect.Float64:
-		floatValue, err := strconv.ParseFloat(envValue, 64)
-		if err != nil {
-			return reflect.Value{}, fieldConstraintError(fieldType.Name, envKey, envValue, "a valid float64")
+		fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+	}
+
+	return fieldValue.Elem()
+}
+
+// fieldByIndex works like reflect.Value.FieldByIndex, but allocates the nil
+// embedded struct pointers it steps through.
+func fieldByIndex(structValue reflect.Value, index []int) reflect.Value {
+	v := structValue
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			v = nestedStruct(v, true)
 		}
 
-		return reflect.ValueOf(floatValue), nil
-	default:
-		return reflect.Value{}, fmt.Errorf("unsupported type for field %q (ENV[%q]): %v", fieldType.Name, envKey, fieldType.Type)
+		v = v.Field(x)
 	}
-}
 
-func parseWithTextUnmarshaler(fieldType reflect.StructField, envKey, envValue string) (reflect.Value, bool, error) {
-	isPointerField := fieldType.Type.Kind() == reflect.Pointer
-	valuePtr := castToValuePtr(fieldType)
+	return v
+}
 
-	if !valuePtr.Type().Implements(textUnmarshalerType) {
-		return reflect.Value{}, false, nil
+// isNestedStruct reports whether an untagged field is a struct whose own
+// fields should be loaded, as opposed to a value type like time.Time.
+// Embedded fields may also be pointers to such structs.
+func isNestedStruct(fieldType reflect.StructField) bool {
+	structType := fieldType.Type
+	if fieldType.Anonymous && structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
 	}
 
-	unmarshaler := valuePtr.Interface().(encoding.TextUnmarshaler)
-	if err := unmarshaler.UnmarshalText([]byte(envValue)); err != nil {
-		return reflect.Value{}, true, fieldConstraintError(fieldType.Name, envKey, envValue, "a valid value for custom text unmarshaler")
+	if !isFieldVisible(fieldType) || structType.Kind() != reflect.Struct {
+		return false
 	}
 
-	if isPointerField {
-		return valuePtr, true, nil
+	if structType == timeType || structType == urlType {
+		return false
 	}
 
-	return valuePtr.Elem(), true, nil
+	return !reflect.PointerTo(structType).Implements(textUnmarshalerType)
 }
 
 func supportsAllowEmpty(fieldType reflect.Type) bool {
-	if fieldType.Kind() == reflect.String {
-		return true
-	}
+	return isStringLike(fieldType) || isByteSlice(fieldType) || (isList(fieldType) && indirectType(fieldType).Kind() == reflect.Slice)
+}
 
-	if fieldType.Kind() == reflect.Pointer {
-		return fieldType.Implements(textUnmarshalerType)
+func isStringLike(fieldType reflect.Type) bool {
+	valueType := indirectType(fieldType)
+	if valueType.Kind() == reflect.String {
+		return true
 	}
 
-	return reflect.PointerTo(fieldType).Implements(textUnmarshalerType)
+	return reflect.PointerTo(valueType).Implements(textUnmarshalerType)
 }
 
 func supportsTrimSpace(fieldType reflect.Type) bool {
@@ -433,7 +2296,94 @@ func supportsTrimSpace(fieldType reflect.Type) bool {
 }
 
 func supportsStringLength(fieldType reflect.Type) bool {
-	return supportsAllowEmpty(fieldType)
+	return isStringLike(fieldType) || (isList(fieldType) && indirectType(fieldType).Kind() == reflect.Slice)
+}
+
+// valueLength is the length checked by minlen and maxlen: the number of
+// elements for slice fields, and the number of characters otherwise.
+func valueLength(fieldType reflect.StructField, fieldTag envTag, envValue string) int {
+	if !isList(fieldType.Type) {
+		return utf8.RuneCountInString(envValue)
+	}
+	if envValue == "" {
+		return 0
+	}
+
+	return strings.Count(envValue, fieldTag.separator) + 1
+}
+
+func isStringMap(fieldType reflect.Type) bool {
+	return fieldType.Kind() == reflect.Map && fieldType.Key().Kind() == reflect.String && fieldType.Elem().Kind() == reflect.String
+}
+
+func isByteSlice(fieldType reflect.Type) bool {
+	valueType := indirectType(fieldType)
+	return valueType.Kind() == reflect.Slice && valueType.Elem().Kind() == reflect.Uint8
+}
+
+// isList reports whether fieldType is parsed as a separated list: a slice
+// other than []byte, or an array.
+func isList(fieldType reflect.Type) bool {
+	valueType := indirectType(fieldType)
+	return (valueType.Kind() == reflect.Slice && !isByteSlice(valueType)) || valueType.Kind() == reflect.Array
+}
+
+func isStringSlice(fieldType reflect.Type) bool {
+	valueType := indirectType(fieldType)
+	return valueType.Kind() == reflect.Slice && valueType.Elem().Kind() == reflect.String
+}
+
+// indirectType returns the element type for pointer fields, so *T fields
+// share the tag rules and constraints of T.
+func indirectType(fieldType reflect.Type) reflect.Type {
+	if fieldType.Kind() == reflect.Pointer {
+		return fieldType.Elem()
+	}
+
+	return fieldType
+}
+
+// isConstraint reports whether a tag option is a constraint checked by
+// validateConstraints.
+func isConstraint(option string) bool {
+	if option == "notempty" {
+		return true
+	}
+
+	for _, prefix := range []string{"oneof=", "minlen=", "maxlen=", "min=", "max=", "gt=", "gte=", "lt=", "lte=", "multipleof=", "prefix=", "suffix=", "contains=", "regex=", "format=", "validate=", "eq="} {
+		if strings.HasPrefix(option, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTagModifier reports whether a tag option changes how a value is read
+// or parsed, rather than constraining it.
+func isTagModifier(option string) bool {
+	switch option {
+	case "", "optional", "required", "allowempty", "trimspace", "ignorecase", "secret", "vprefix", "json", "char", "size", "percent", "collect":
+		return true
+	}
+
+	for _, prefix := range []string{"sep=", "layout=", "default=", "schemes=", "secret=", "alias=", "transform=", "deprecated=", "trim="} {
+		if strings.HasPrefix(option, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func lookupTagOption(tagOptions []string, prefix string) (string, bool) {
+	for _, option := range tagOptions[1:] {
+		if strings.HasPrefix(option, prefix) {
+			return strings.TrimPrefix(option, prefix), true
+		}
+	}
+
+	return "", false
 }
 
 func hasLengthConstraint(tagOptions []string) bool {
@@ -446,27 +2396,42 @@ func hasLengthConstraint(tagOptions []string) bool {
 	return false
 }
 
+// hasBoundConstraint reports whether tagOptions contains a min, max, gt, gte,
+// lt, or lte bound.
+func hasBoundConstraint(tagOptions []string) bool {
+	for _, option := range tagOptions[1:] {
+		name, _, _ := strings.Cut(option, "=")
+		switch name {
+		case "min", "max", "gt", "gte", "lt", "lte":
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasNumericConstraint(tagOptions []string) bool {
+	for _, option := range tagOptions[1:] {
+		name, _, _ := strings.Cut(option, "=")
+		switch name {
+		case "min", "max", "gt", "gte", "lt", "lte", "multipleof":
+			return true
+		}
+	}
+
+	return false
+}
+
 func parseLenConstraint(fieldType reflect.StructField, envKey, constraint, prefix string) (int, error) {
 	valueStr := strings.TrimPrefix(constraint, prefix)
 	value, err := strconv.Atoi(valueStr)
 	if err != nil || value < 0 {
-		return 0, fmt.Errorf("invalid tag for field %q (ENV[%q]): %q must be a valid non-negative integer", fieldType.Name, envKey, constraint)
+		return 0, tagError(fieldType.Name, envKey, "%q must be a valid non-negative integer", constraint)
 	}
 
 	return value, nil
 }
 
-func castToValuePtr(fieldType reflect.StructField) reflect.Value {
-	var valuePtr reflect.Value
-	if fieldType.Type.Kind() == reflect.Pointer {
-		valuePtr = reflect.New(fieldType.Type.Elem())
-	} else {
-		valuePtr = reflect.New(fieldType.Type)
-	}
-
-	return valuePtr
-}
-
 func assignFieldValue(field reflect.Value, val reflect.Value) error {
 	if !field.IsValid() {
 		return errors.New("field is not valid")
@@ -484,17 +2449,54 @@ func assignFieldValue(field reflect.Value, val reflect.Value) error {
 	return nil
 }
 
-func matchRegex(pattern, str string) (bool, error) {
+// regexCache holds compiled regex constraints keyed by pattern, so repeated
+// loads don't recompile identical patterns.
+var regexCache sync.Map
+
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
 	re, err := regexp.Compile(pattern)
 	if err != nil {
-		return false, fmt.Errorf("failed to compile env config regex '%v': %v", pattern, err)
+		return nil, err
+	}
+
+	regexCache.Store(pattern, re)
+	return re, nil
+}
+
+// matchRegex reports whether str matches pattern. The error is set only when
+// pattern does not compile, so callers can tell a bad tag from a bad value.
+func matchRegex(pattern, str string) (bool, error) {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return false, err
 	}
 
-	matches := re.MatchString(str)
-	if !matches {
-		return false, errors.New("env var value does not match given pattern")
+	return re.MatchString(str), nil
+}
+
+// splitOptionList splits a comma-separated constraint list such as the one in
+// oneof=a,b,c. A comma escaped as \, is kept as part of the option.
+func splitOptionList(list string) []string {
+	var opts []string
+	var opt strings.Builder
+	for i := 0; i < len(list); i++ {
+		switch {
+		case list[i] == '\\' && i+1 < len(list) && list[i+1] == ',':
+			opt.WriteByte(',')
+			i++
+		case list[i] == ',':
+			opts = append(opts, opt.String())
+			opt.Reset()
+		default:
+			opt.WriteByte(list[i])
+		}
 	}
-	return true, nil
+
+	return append(opts, opt.String())
 }
 
 func normalizeQuotedValue(s string) string {
@@ -509,13 +2511,14 @@ func normalizeQuotedValue(s string) string {
 	return s
 }
 
-func isValidURL(s string) bool {
+var defaultURLSchemes = []string{"http", "https"}
+
+func isValidURL(s string, allowedSchemes []string) bool {
 	u, err := url.Parse(s)
 	if err != nil || u.Scheme == "" || u.Host == "" {
 		return false
 	}
 
-	allowedSchemes := []string{"http", "https"}
 	for _, scheme := range allowedSchemes {
 		if strings.EqualFold(u.Scheme, scheme) {
 			return true
@@ -525,10 +2528,10 @@ func isValidURL(s string) bool {
 	return false
 }
 
-func validateFormat(format, value string) (expected string, ok bool) {
+func validateFormat(format, value string, fieldTag envTag) (expected string, ok bool) {
 	switch format {
 	case "URL":
-		return "a valid URL with http/https scheme", isValidURL(value)
+		return fmt.Sprintf("a valid URL with %s scheme", strings.Join(fieldTag.schemes, "/")), isValidURL(value, fieldTag.schemes)
 	case "URI":
 		return "a valid URI with scheme", isValidURI(value)
 	case "FILE":
@@ -536,17 +2539,34 @@ func validateFormat(format, value string) (expected string, ok bool) {
 	case "DIR":
 		return "an existing directory path", isExistingDir(value)
 	case "HOSTPORT":
-		return "a valid host:port value", isValidHostPort(value)
+		return "a valid host:port value with a port in range 1-65535", isValidHostPort(value)
 	case "UUID":
 		return "a valid UUID", isValidUUID(value)
 	case "IP":
 		return "a valid IPv4 or IPv6 address", isValidIP(value)
+	case "IPV4":
+		return "a valid IPv4 address", isValidIPv4(value)
+	case "IPV6":
+		return "a valid IPv6 address", isValidIPv6(value)
 	case "HEX":
 		return "a valid hexadecimal value", isHex(value)
 	case "ALPHANUMERIC":
 		return "a value containing only letters and numbers", isAlphanumeric(value)
 	case "IDENTIFIER":
 		return "a value containing only letters, numbers, underscores, or hyphens", isIdentifier(value)
+	case "EMAIL":
+		return "a valid email address", isValidEmail(value)
+	case "JSON":
+		return "valid JSON", json.Valid([]byte(value))
+	case "BASE64":
+		_, err := base64.StdEncoding.DecodeString(value)
+		return "valid base64", err == nil
+	case "SEMVER":
+		if fieldTag.vPrefix {
+			return "a valid semantic version (for example: 1.2.3 or v1.2.3)", isValidSemver(strings.TrimPrefix(value, "v"))
+		}
+
+		return "a valid semantic version (for example: 1.2.3)", isValidSemver(value)
 	default:
 		return "", false
 	}
@@ -580,8 +2600,20 @@ func isExistingDir(path string) bool {
 }
 
 func isValidHostPort(value string) bool {
-	_, _, err := net.SplitHostPort(value)
-	return err == nil
+	_, portStr, err := net.SplitHostPort(value)
+	if err != nil {
+		return false
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	return err == nil && port >= 1
+}
+
+// semverPattern is the semantic versioning 2.0.0 grammar from semver.org.
+var semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+func isValidSemver(value string) bool {
+	return semverPattern.MatchString(value)
 }
 
 func isValidUUID(value string) bool {
@@ -593,6 +2625,14 @@ func isValidIP(value string) bool {
 	return net.ParseIP(value) != nil
 }
 
+func isValidIPv4(value string) bool {
+	return isValidIP(value) && !strings.Contains(value, ":")
+}
+
+func isValidIPv6(value string) bool {
+	return isValidIP(value) && strings.Contains(value, ":")
+}
+
 func isHex(value string) bool {
 	match, _ := regexp.MatchString(`^[0-9a-fA-F]+$`, value)
 	return match
@@ -607,3 +2647,8 @@ func isIdentifier(value string) bool {
 	match, _ := regexp.MatchString(`^[A-Za-z0-9_-]+$`, value)
 	return match
 }
+
+func isValidEmail(value string) bool {
+	addr, err := mail.ParseAddress(value)
+	return err == nil && addr.Address == value
+}
@@ -1,7 +1,14 @@
 package simpleenv
 
 import (
+	"errors"
+	"io"
+	"maps"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -19,6 +26,20 @@ func (c *customToken) UnmarshalText(text []byte) error {
 	return nil
 }
 
+type logLevel string
+
+var errUnknownLogLevel = errors.New("unknown log level")
+
+func (l *logLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "debug", "info", "warn", "error":
+		*l = logLevel(text)
+		return nil
+	default:
+		return errUnknownLogLevel
+	}
+}
+
 func unsetEnv(t *testing.T, key string) {
 	t.Helper()
 
@@ -68,8 +89,12 @@ func TestLoadInputValidation(t *testing.T) {
 	}{
 		{name: "nil input", input: nil, contains: "invalid Load input"},
 		{name: "struct by value", input: struct{}{}, contains: "pass &cfg"},
-		{name: "nil pointer", input: (*int)(nil), contains: "invalid Load input"},
-		{name: "pointer to non-struct", input: &x, contains: "invalid Load input"},
+		{name: "nil pointer", input: (*int)(nil), contains: "got nil *int"},
+		{name: "nil struct pointer", input: (*struct{})(nil), contains: "got nil *struct {}"},
+		{name: "pointer to non-struct", input: &x, contains: "got *int"},
+		{name: "pointer to pointer", input: new(*struct{}), contains: "got **struct {}"},
+		{name: "non-pointer non-struct", input: x, contains: "got int"},
+		{name: "map", input: map[string]string{}, contains: "got map[string]string"},
 	}
 
 	for _, tt := range tests {
@@ -78,6 +103,9 @@ func TestLoadInputValidation(t *testing.T) {
 			if err == nil {
 				t.Fatal("expected error, got nil")
 			}
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Fatalf("expected errors.Is(err, ErrInvalidInput), got %v", err)
+			}
 			if !strings.Contains(err.Error(), tt.contains) {
 				t.Fatalf("expected error to contain %q, got %q", tt.contains, err.Error())
 			}
@@ -109,6 +137,42 @@ func TestLoadBehaviorCases(t *testing.T) {
 			envValue:  nil,
 			wantValue: float64(0),
 		},
+		{
+			name:      "optional bool missing keeps zero",
+			fieldType: reflect.TypeOf(false),
+			tag:       "SIMPLEENV_TEST_OPTIONAL_BOOL;optional",
+			envValue:  nil,
+			wantValue: false,
+		},
+		{
+			name:      "optional int present but empty keeps zero",
+			fieldType: reflect.TypeOf(int(0)),
+			tag:       "SIMPLEENV_TEST_OPTIONAL_INT_EMPTY;optional",
+			envValue:  strPtr(""),
+			wantValue: int(0),
+		},
+		{
+			name:      "optional float present but empty keeps zero",
+			fieldType: reflect.TypeOf(float64(0)),
+			tag:       "SIMPLEENV_TEST_OPTIONAL_FLOAT_EMPTY;optional",
+			envValue:  strPtr(""),
+			wantValue: float64(0),
+		},
+		{
+			name:      "optional bool present but empty keeps zero",
+			fieldType: reflect.TypeOf(false),
+			tag:       "SIMPLEENV_TEST_OPTIONAL_BOOL_EMPTY;optional",
+			envValue:  strPtr(""),
+			wantValue: false,
+		},
+		{
+			name:        "optional notempty present but empty returns error",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_OPTIONAL_NOTEMPTY_EMPTY;optional;notempty",
+			envValue:    strPtr(""),
+			wantErr:     true,
+			errContains: []string{"non-empty value"},
+		},
 		{
 			name:      "optional with min missing skips validation",
 			fieldType: reflect.TypeOf(int(0)),
@@ -155,6 +219,45 @@ func TestLoadBehaviorCases(t *testing.T) {
 			wantErr:     true,
 			errContains: []string{"a value <= 3s"},
 		},
+		{
+			name:        "duration below sub-unit min returns error",
+			fieldType:   reflect.TypeOf(time.Duration(0)),
+			tag:         "SIMPLEENV_TEST_DURATION_TIMEOUT;min=1s;max=1m",
+			envValue:    strPtr("500ms"),
+			wantErr:     true,
+			errContains: []string{`got "500ms"`, "a value >= 1s"},
+		},
+		{
+			name:        "duration above minute max returns error",
+			fieldType:   reflect.TypeOf(time.Duration(0)),
+			tag:         "SIMPLEENV_TEST_DURATION_TIMEOUT;min=1s;max=1m",
+			envValue:    strPtr("1m0.5s"),
+			wantErr:     true,
+			errContains: []string{"a value <= 1m"},
+		},
+		{
+			name:      "duration pointer with gt and lt succeeds",
+			fieldType: reflect.TypeOf((*time.Duration)(nil)),
+			tag:       "SIMPLEENV_TEST_DURATION_PTR_BOUNDED;gt=0s;lt=1h",
+			envValue:  strPtr("90s"),
+			wantValue: func() *time.Duration { d := 90 * time.Second; return &d }(),
+		},
+		{
+			name:        "duration invalid bound is reported when unset",
+			fieldType:   reflect.TypeOf(time.Duration(0)),
+			tag:         "SIMPLEENV_TEST_DURATION_MIN_UNSET;optional;min=1",
+			envValue:    nil,
+			wantErr:     true,
+			errContains: []string{`"min=1" must be a valid duration`},
+		},
+		{
+			name:        "numeric invalid bound is reported when unset",
+			fieldType:   reflect.TypeOf(int(0)),
+			tag:         "SIMPLEENV_TEST_INT_MAX_UNSET;optional;max=1s",
+			envValue:    nil,
+			wantErr:     true,
+			errContains: []string{`"max=1s" must be a valid number`},
+		},
 		{
 			name:        "duration with invalid min constraint returns error",
 			fieldType:   reflect.TypeOf(time.Duration(0)),
@@ -171,6 +274,119 @@ func TestLoadBehaviorCases(t *testing.T) {
 			wantErr:     true,
 			errContains: []string{"valid duration for min comparison"},
 		},
+		{
+			name:        "invalid duration returns duration error",
+			fieldType:   reflect.TypeOf(time.Duration(0)),
+			tag:         "SIMPLEENV_TEST_DURATION_INVALID",
+			envValue:    strPtr("banana"),
+			wantErr:     true,
+			errContains: []string{`got "banana"`, "a valid time.Duration"},
+		},
+		{
+			name:        "duration does not accept raw nanoseconds",
+			fieldType:   reflect.TypeOf(time.Duration(0)),
+			tag:         "SIMPLEENV_TEST_DURATION_RAW_INT",
+			envValue:    strPtr("1500"),
+			wantErr:     true,
+			errContains: []string{"a valid time.Duration"},
+		},
+		{
+			name:      "time with default RFC3339 layout",
+			fieldType: reflect.TypeOf(time.Time{}),
+			tag:       "SIMPLEENV_TEST_TIME_DEFAULT",
+			envValue:  strPtr("2026-03-02T10:30:00Z"),
+			wantValue: time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "time with custom layout",
+			fieldType: reflect.TypeOf(time.Time{}),
+			tag:       "SIMPLEENV_TEST_TIME_LAYOUT;layout=2006-01-02",
+			envValue:  strPtr("2026-03-02"),
+			wantValue: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "time not matching layout returns error",
+			fieldType:   reflect.TypeOf(time.Time{}),
+			tag:         "SIMPLEENV_TEST_TIME_INVALID;layout=2006-01-02",
+			envValue:    strPtr("03/02/2026"),
+			wantErr:     true,
+			errContains: []string{`got "03/02/2026"`, `layout "2006-01-02"`},
+		},
+		{
+			name:        "layout on string is invalid",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_LAYOUT_STRING;layout=2006-01-02",
+			envValue:    strPtr("2026-03-02"),
+			wantErr:     true,
+			errContains: []string{"layout is only supported"},
+		},
+		{
+			name:      "optional pointer missing stays nil",
+			fieldType: reflect.TypeOf((*int)(nil)),
+			tag:       "SIMPLEENV_TEST_OPTIONAL_PTR;optional",
+			envValue:  nil,
+			wantValue: (*int)(nil),
+		},
+		{
+			name:        "pointer values are validated like their element",
+			fieldType:   reflect.TypeOf((*time.Duration)(nil)),
+			tag:         "SIMPLEENV_TEST_PTR_DURATION_MIN;min=5s",
+			envValue:    strPtr("2s"),
+			wantErr:     true,
+			errContains: []string{"a value >= 5s"},
+		},
+		{
+			name:      "default used when missing",
+			fieldType: reflect.TypeOf(int(0)),
+			tag:       "SIMPLEENV_TEST_DEFAULT_INT;default=8080",
+			envValue:  nil,
+			wantValue: int(8080),
+		},
+		{
+			name:      "default duration used when missing",
+			fieldType: reflect.TypeOf(time.Duration(0)),
+			tag:       "SIMPLEENV_TEST_DEFAULT_DURATION;default=30s;min=1s",
+			envValue:  nil,
+			wantValue: 30 * time.Second,
+		},
+		{
+			name:      "env value wins over default",
+			fieldType: reflect.TypeOf(true),
+			tag:       "SIMPLEENV_TEST_DEFAULT_BOOL;default=false",
+			envValue:  strPtr("true"),
+			wantValue: true,
+		},
+		{
+			name:        "default violating constraint returns error",
+			fieldType:   reflect.TypeOf(int(0)),
+			tag:         "SIMPLEENV_TEST_DEFAULT_INVALID;default=0;min=1",
+			envValue:    nil,
+			wantErr:     true,
+			errContains: []string{"invalid default", "a value >= 1"},
+		},
+		{
+			name:        "explicit required missing returns error",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_EXPLICIT_REQUIRED;required",
+			envValue:    nil,
+			wantErr:     true,
+			errContains: []string{"<unset>"},
+		},
+		{
+			name:      "explicit required present succeeds",
+			fieldType: reflect.TypeOf(""),
+			tag:       "SIMPLEENV_TEST_EXPLICIT_REQUIRED_SET;required;minlen=2",
+			envValue:  strPtr("ok"),
+			wantValue: "ok",
+		},
+		{
+			name:        "optional and required together is invalid",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_OPTIONAL_REQUIRED;optional;required",
+			envValue:    strPtr("x"),
+			wantErr:     true,
+			errContains: []string{"optional and required cannot be used together"},
+		},
 		{
 			name:        "required missing returns error",
 			fieldType:   reflect.TypeOf(int(0)),
@@ -188,12 +404,11 @@ func TestLoadBehaviorCases(t *testing.T) {
 			errContains: []string{"valid int"},
 		},
 		{
-			name:        "optional present empty string returns error",
-			fieldType:   reflect.TypeOf(""),
-			tag:         "SIMPLEENV_TEST_OPTIONAL_EMPTY;optional",
-			envValue:    strPtr(""),
-			wantErr:     true,
-			errContains: []string{"non-empty value"},
+			name:      "optional present empty string keeps zero",
+			fieldType: reflect.TypeOf(""),
+			tag:       "SIMPLEENV_TEST_OPTIONAL_EMPTY;optional",
+			envValue:  strPtr(""),
+			wantValue: "",
 		},
 		{
 			name:        "required present empty string returns error",
@@ -217,6 +432,29 @@ func TestLoadBehaviorCases(t *testing.T) {
 			envValue:  strPtr("  dev  "),
 			wantValue: "dev",
 		},
+		{
+			name:        "oneof is case-sensitive by default",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_ONEOF_CASE;oneof=dev,prod",
+			envValue:    strPtr("PROD"),
+			wantErr:     true,
+			errContains: []string{"one of [dev,prod]"},
+		},
+		{
+			name:      "oneof with ignorecase matches any case",
+			fieldType: reflect.TypeOf(""),
+			tag:       "SIMPLEENV_TEST_ONEOF_IGNORECASE;oneof=development,production;ignorecase",
+			envValue:  strPtr("Production"),
+			wantValue: "Production",
+		},
+		{
+			name:        "ignorecase without oneof is invalid",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_IGNORECASE_ALONE;ignorecase",
+			envValue:    strPtr("x"),
+			wantErr:     true,
+			errContains: []string{"ignorecase requires a oneof constraint"},
+		},
 		{
 			name:      "minlen and maxlen both succeed",
 			fieldType: reflect.TypeOf(""),
@@ -230,7 +468,7 @@ func TestLoadBehaviorCases(t *testing.T) {
 			tag:         "SIMPLEENV_TEST_LEN_MIN_FAIL;minlen=4",
 			envValue:    strPtr("abc"),
 			wantErr:     true,
-			errContains: []string{"length >= 4"},
+			errContains: []string{"length >= 4 (got length 3)"},
 		},
 		{
 			name:        "maxlen fails when value too long",
@@ -238,7 +476,15 @@ func TestLoadBehaviorCases(t *testing.T) {
 			tag:         "SIMPLEENV_TEST_LEN_MAX_FAIL;maxlen=3",
 			envValue:    strPtr("abcd"),
 			wantErr:     true,
-			errContains: []string{"length <= 3"},
+			errContains: []string{"length <= 3 (got length 4)"},
+		},
+		{
+			name:        "maxlen counts runes not bytes",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_LEN_RUNES;minlen=3;maxlen=4",
+			envValue:    strPtr("héllo"),
+			wantErr:     true,
+			errContains: []string{"length <= 4 (got length 5)"},
 		},
 		{
 			name:      "trimspace applies before minlen",
@@ -270,6 +516,28 @@ func TestLoadBehaviorCases(t *testing.T) {
 			envValue:  strPtr("   \t\n  "),
 			wantValue: "",
 		},
+		{
+			name:      "trim strips trailing slashes",
+			fieldType: reflect.TypeOf(""),
+			tag:       "SIMPLEENV_TEST_TRIM_SLASH;trim=/",
+			envValue:  strPtr("/var/data//"),
+			wantValue: "var/data",
+		},
+		{
+			name:      "trim strips quotes after trimspace and before validation",
+			fieldType: reflect.TypeOf(""),
+			tag:       `SIMPLEENV_TEST_TRIM_QUOTES;trimspace;trim=\"'\";oneof=dev,prod`,
+			envValue:  strPtr("  'prod' "),
+			wantValue: "prod",
+		},
+		{
+			name:        "trim leaving nothing is an empty value",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_TRIM_EMPTY;trim=/",
+			envValue:    strPtr("//"),
+			wantErr:     true,
+			errContains: []string{"expected a non-empty value"},
+		},
 		{
 			name:        "allowempty does not bypass other rules",
 			fieldType:   reflect.TypeOf(""),
@@ -300,6 +568,22 @@ func TestLoadBehaviorCases(t *testing.T) {
 			wantErr:     true,
 			errContains: []string{"unsupported constraint"},
 		},
+		{
+			name:        "misspelled option is reported when the env var is missing",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_TYPO_OPTIONAL;optonal",
+			envValue:    nil,
+			wantErr:     true,
+			errContains: []string{`unsupported constraint "optonal"`},
+		},
+		{
+			name:        "misspelled option is reported when a default is used",
+			fieldType:   reflect.TypeOf(int(0)),
+			tag:         "SIMPLEENV_TEST_TYPO_DEFAULT;default=1;mni=1",
+			envValue:    nil,
+			wantErr:     true,
+			errContains: []string{`unsupported constraint "mni=1"`},
+		},
 		{
 			name:      "regex supports quoted pattern",
 			fieldType: reflect.TypeOf(""),
@@ -307,6 +591,37 @@ func TestLoadBehaviorCases(t *testing.T) {
 			envValue:  strPtr("http://localhost:8085"),
 			wantValue: "http://localhost:8085",
 		},
+		{
+			name:      "regex supports double quoted pattern",
+			fieldType: reflect.TypeOf(""),
+			tag:       `SIMPLEENV_TEST_REGEX_DOUBLE_QUOTED;regex=\"^v[0-9]+$\"`,
+			envValue:  strPtr("v12"),
+			wantValue: "v12",
+		},
+		{
+			name:        "regex with quoted pattern rejects non-matching value",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_REGEX_QUOTED_MISMATCH;regex='^(http|https)://localhost:[0-9]+$'",
+			envValue:    strPtr("ftp://localhost:21"),
+			wantErr:     true,
+			errContains: []string{"to match regex"},
+		},
+		{
+			name:        "invalid regex pattern is a tag error",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_REGEX_INVALID;regex=(",
+			envValue:    strPtr("anything"),
+			wantErr:     true,
+			errContains: []string{"regex=( is not a valid pattern", "missing closing )"},
+		},
+		{
+			name:        "invalid regex pattern is reported even when unset",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_REGEX_INVALID_UNSET;optional;regex='[a-'",
+			envValue:    nil,
+			wantErr:     true,
+			errContains: []string{"is not a valid pattern"},
+		},
 		{
 			name:        "unknown format returns error",
 			fieldType:   reflect.TypeOf(""),
@@ -316,135 +631,2866 @@ func TestLoadBehaviorCases(t *testing.T) {
 			errContains: []string{"unsupported format"},
 		},
 		{
-			name:        "allowempty on int is invalid",
+			name:        "constraints after format still run",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_FORMAT_THEN_MAXLEN;format=HEX;maxlen=4",
+			envValue:    strPtr("abcdef"),
+			wantErr:     true,
+			errContains: []string{"length <= 4"},
+		},
+		{
+			name:        "unknown format before min returns error",
 			fieldType:   reflect.TypeOf(int(0)),
-			tag:         "SIMPLEENV_TEST_ALLOWEMPTY_INT;allowempty",
+			tag:         "SIMPLEENV_TEST_UNKNOWN_FORMAT_MIN;format=foo;min=1",
+			envValue:    strPtr("0"),
+			wantErr:     true,
+			errContains: []string{"unsupported format"},
+		},
+		{
+			name:        "notempty rejects whitespace-only values",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_NOTEMPTY_SPACES;notempty",
+			envValue:    strPtr("   "),
+			wantErr:     true,
+			errContains: []string{`got "   "`, "a value that is not empty or whitespace-only"},
+		},
+		{
+			name:      "notempty accepts padded values",
+			fieldType: reflect.TypeOf(""),
+			tag:       "SIMPLEENV_TEST_NOTEMPTY_PADDED;notempty",
+			envValue:  strPtr(" app "),
+			wantValue: " app ",
+		},
+		{
+			name:        "notempty with allowempty is invalid",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_NOTEMPTY_ALLOWEMPTY;allowempty;notempty",
 			envValue:    strPtr(""),
 			wantErr:     true,
-			errContains: []string{"allowempty is only supported"},
+			errContains: []string{"allowempty and notempty cannot be used together"},
 		},
 		{
-			name:        "trimspace on int is invalid",
-			fieldType:   reflect.TypeOf(int(0)),
-			tag:         "SIMPLEENV_TEST_TRIMSPACE_INT;trimspace",
-			envValue:    strPtr("12"),
+			name:      "prefix accepts matching values",
+			fieldType: reflect.TypeOf(""),
+			tag:       "SIMPLEENV_TEST_PREFIX_OK;prefix=gs://",
+			envValue:  strPtr("gs://bucket/path"),
+			wantValue: "gs://bucket/path",
+		},
+		{
+			name:      "prefix accepts any of several values",
+			fieldType: reflect.TypeOf(""),
+			tag:       "SIMPLEENV_TEST_PREFIX_ANY;prefix=gs://,s3://",
+			envValue:  strPtr("s3://bucket"),
+			wantValue: "s3://bucket",
+		},
+		{
+			name:        "prefix rejects other values",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_PREFIX_FAIL;prefix=gs://,s3://",
+			envValue:    strPtr("https://bucket"),
 			wantErr:     true,
-			errContains: []string{"trimspace is only supported"},
+			errContains: []string{"a value starting with one of [gs://,s3://]"},
 		},
 		{
-			name:        "minlen on int is invalid",
+			name:      "suffix accepts matching values",
+			fieldType: reflect.TypeOf(""),
+			tag:       "SIMPLEENV_TEST_SUFFIX_OK;suffix=.pem",
+			envValue:  strPtr("/etc/tls/key.pem"),
+			wantValue: "/etc/tls/key.pem",
+		},
+		{
+			name:        "suffix rejects other values",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_SUFFIX_FAIL;suffix=.pem,.crt",
+			envValue:    strPtr("/etc/tls/key.txt"),
+			wantErr:     true,
+			errContains: []string{"a value ending with one of [.pem,.crt]"},
+		},
+		{
+			name:        "suffix on int field is invalid",
 			fieldType:   reflect.TypeOf(int(0)),
-			tag:         "SIMPLEENV_TEST_MINLEN_INT;minlen=1",
-			envValue:    strPtr("12"),
+			tag:         "SIMPLEENV_TEST_SUFFIX_INT;suffix=0",
+			envValue:    strPtr("10"),
 			wantErr:     true,
-			errContains: []string{"minlen/maxlen are only supported"},
+			errContains: []string{"suffix is only supported for string"},
 		},
 		{
-			name:        "invalid minlen value returns error",
+			name:      "contains accepts values with the substring",
+			fieldType: reflect.TypeOf(""),
+			tag:       "SIMPLEENV_TEST_CONTAINS_OK;contains=sslmode=",
+			envValue:  strPtr("postgres://db/app?sslmode=require"),
+			wantValue: "postgres://db/app?sslmode=require",
+		},
+		{
+			name:        "contains names the missing substring",
 			fieldType:   reflect.TypeOf(""),
-			tag:         "SIMPLEENV_TEST_MINLEN_INVALID;minlen=nope",
-			envValue:    strPtr("abc"),
+			tag:         "SIMPLEENV_TEST_CONTAINS_FAIL;contains=sslmode=",
+			envValue:    strPtr("postgres://db/app"),
 			wantErr:     true,
-			errContains: []string{"valid non-negative integer"},
+			errContains: []string{`expected a value containing "sslmode="`},
 		},
 		{
-			name:        "negative maxlen value returns error",
+			name:        "contains cannot be empty",
 			fieldType:   reflect.TypeOf(""),
-			tag:         "SIMPLEENV_TEST_MAXLEN_NEGATIVE;maxlen=-1",
-			envValue:    strPtr("abc"),
+			tag:         "SIMPLEENV_TEST_CONTAINS_EMPTY;contains=",
+			envValue:    strPtr("x"),
 			wantErr:     true,
-			errContains: []string{"valid non-negative integer"},
+			errContains: []string{"contains cannot be empty"},
 		},
 		{
-			name:        "min does not trim whitespace automatically",
-			fieldType:   reflect.TypeOf(int(0)),
-			tag:         "SIMPLEENV_TEST_MIN_WHITESPACE;min=1",
-			envValue:    strPtr(" 2 "),
+			name:      "oneof keeps escaped commas inside an option",
+			fieldType: reflect.TypeOf(""),
+			tag:       `SIMPLEENV_TEST_ONEOF_ESCAPED;oneof=a\\,b,c`,
+			envValue:  strPtr("a,b"),
+			wantValue: "a,b",
+		},
+		{
+			name:      "oneof with escaped comma still matches other options",
+			fieldType: reflect.TypeOf(""),
+			tag:       `SIMPLEENV_TEST_ONEOF_ESCAPED_OTHER;oneof=a\\,b,c`,
+			envValue:  strPtr("c"),
+			wantValue: "c",
+		},
+		{
+			name:        "oneof with escaped comma rejects its halves",
+			fieldType:   reflect.TypeOf(""),
+			tag:         `SIMPLEENV_TEST_ONEOF_ESCAPED_HALF;oneof=a\\,b,c`,
+			envValue:    strPtr("b"),
 			wantErr:     true,
-			errContains: []string{"numeric value for min comparison"},
+			errContains: []string{"one of"},
 		},
 		{
-			name:        "error message includes field env and expected",
-			fieldType:   reflect.TypeOf(int(0)),
-			tag:         "SIMPLEENV_TEST_ERROR_SHAPE;min=1",
-			envValue:    strPtr("abc"),
+			name:      "JSON format keeps the raw string",
+			fieldType: reflect.TypeOf(""),
+			tag:       "SIMPLEENV_TEST_JSON_RAW;format=JSON",
+			envValue:  strPtr(`{"beta": true}`),
+			wantValue: `{"beta": true}`,
+		},
+		{
+			name:        "JSON format error says the value is not valid JSON",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_JSON_INVALID;format=JSON",
+			envValue:    strPtr(`{"beta": true`),
 			wantErr:     true,
-			errContains: []string{`field "Value"`, `ENV["SIMPLEENV_TEST_ERROR_SHAPE"]`, "expected"},
+			errContains: []string{`got "{\"beta\": true"`, "expected valid JSON"},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			value, err := loadSingleField(t, tt.fieldType, tt.tag, tt.envValue)
-			if tt.wantErr {
-				if err == nil {
-					t.Fatal("expected error, got nil")
-				}
-				for _, contains := range tt.errContains {
-					if !strings.Contains(err.Error(), contains) {
-						t.Fatalf("expected error to contain %q, got %q", contains, err.Error())
-					}
-				}
-				return
-			}
-
-			if err != nil {
-				t.Fatalf("expected no error, got %v", err)
-			}
+		{
+			name:        "ipv4 format error names the family",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_IPV4_ERROR;format=ipv4",
+			envValue:    strPtr("::1"),
+			wantErr:     true,
+			errContains: []string{"a valid IPv4 address"},
+		},
+		{
+			name:      "URL with custom schemes succeeds",
+			fieldType: reflect.TypeOf(""),
+			tag:       "SIMPLEENV_TEST_URL_SCHEMES;format=URL;schemes=redis,rediss",
+			envValue:  strPtr("rediss://cache.local:6380/0"),
+			wantValue: "rediss://cache.local:6380/0",
+		},
+		{
+			name:        "URL with custom schemes rejects default schemes",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_URL_SCHEMES_HTTP;format=URL;schemes=postgres",
+			envValue:    strPtr("http://db.local"),
+			wantErr:     true,
+			errContains: []string{"a valid URL with postgres scheme"},
+		},
+		{
+			name:        "URL rejects non-http schemes by default",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_URL_DEFAULT_SCHEMES;format=URL",
+			envValue:    strPtr("amqp://broker.local"),
+			wantErr:     true,
+			errContains: []string{"a valid URL with http/https scheme"},
+		},
+		{
+			name:        "schemes without URL format is invalid",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_SCHEMES_ALONE;schemes=redis",
+			envValue:    strPtr("redis://cache.local"),
+			wantErr:     true,
+			errContains: []string{"schemes requires format=URL"},
+		},
+		{
+			name:        "allowempty on int is invalid",
+			fieldType:   reflect.TypeOf(int(0)),
+			tag:         "SIMPLEENV_TEST_ALLOWEMPTY_INT;allowempty",
+			envValue:    strPtr(""),
+			wantErr:     true,
+			errContains: []string{"allowempty is only supported"},
+		},
+		{
+			name:        "trimspace on int is invalid",
+			fieldType:   reflect.TypeOf(int(0)),
+			tag:         "SIMPLEENV_TEST_TRIMSPACE_INT;trimspace",
+			envValue:    strPtr("12"),
+			wantErr:     true,
+			errContains: []string{"trimspace is only supported"},
+		},
+		{
+			name:        "trim on int is invalid",
+			fieldType:   reflect.TypeOf(int(0)),
+			tag:         "SIMPLEENV_TEST_TRIM_INT;trim=0",
+			envValue:    strPtr("12"),
+			wantErr:     true,
+			errContains: []string{"trim is only supported"},
+		},
+		{
+			name:        "empty trim is invalid",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_TRIM_BLANK;trim=",
+			envValue:    strPtr("a"),
+			wantErr:     true,
+			errContains: []string{"trim cannot be empty"},
+		},
+		{
+			name:      "minlen on a slice counts elements",
+			fieldType: reflect.TypeOf([]string{}),
+			tag:       "SIMPLEENV_TEST_MINLEN_SLICE;minlen=2",
+			envValue:  strPtr("a,b"),
+			wantValue: []string{"a", "b"},
+		},
+		{
+			name:        "minlen on a slice rejects too few elements",
+			fieldType:   reflect.TypeOf([]string{}),
+			tag:         "SIMPLEENV_TEST_MINLEN_SLICE;minlen=2",
+			envValue:    strPtr("long-server-name"),
+			wantErr:     true,
+			errContains: []string{"expected a list with >= 2 elements (got 1)"},
+		},
+		{
+			name:        "minlen on a slice rejects an allowed empty value",
+			fieldType:   reflect.TypeOf([]string{}),
+			tag:         "SIMPLEENV_TEST_MINLEN_SLICE_EMPTY;allowempty;minlen=1",
+			envValue:    strPtr(""),
+			wantErr:     true,
+			errContains: []string{"expected a list with >= 1 elements (got 0)"},
+		},
+		{
+			name:        "maxlen on a typed slice uses its separator",
+			fieldType:   reflect.TypeOf([]int{}),
+			tag:         "SIMPLEENV_TEST_MAXLEN_SLICE;sep=|;maxlen=2",
+			envValue:    strPtr("1|2|3"),
+			wantErr:     true,
+			errContains: []string{"expected a list with <= 2 elements (got 3)"},
+		},
+		{
+			name:        "minlen on an array is invalid",
+			fieldType:   reflect.TypeOf([2]int{}),
+			tag:         "SIMPLEENV_TEST_MINLEN_ARRAY;minlen=1",
+			envValue:    strPtr("1,2"),
+			wantErr:     true,
+			errContains: []string{"minlen/maxlen are only supported"},
+		},
+		{
+			name:        "minlen on int is invalid",
+			fieldType:   reflect.TypeOf(int(0)),
+			tag:         "SIMPLEENV_TEST_MINLEN_INT;minlen=1",
+			envValue:    strPtr("12"),
+			wantErr:     true,
+			errContains: []string{"minlen/maxlen are only supported"},
+		},
+		{
+			name:        "invalid minlen value returns error",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_MINLEN_INVALID;minlen=nope",
+			envValue:    strPtr("abc"),
+			wantErr:     true,
+			errContains: []string{"valid non-negative integer"},
+		},
+		{
+			name:        "negative maxlen value returns error",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_MAXLEN_NEGATIVE;maxlen=-1",
+			envValue:    strPtr("abc"),
+			wantErr:     true,
+			errContains: []string{"valid non-negative integer"},
+		},
+		{
+			name:        "int64 min is compared exactly above 2^53",
+			fieldType:   reflect.TypeOf(int64(0)),
+			tag:         "SIMPLEENV_TEST_INT64_MIN_PRECISE;min=9007199254740993",
+			envValue:    strPtr("9007199254740992"),
+			wantErr:     true,
+			errContains: []string{"a value >= 9007199254740993"},
+		},
+		{
+			name:      "int64 at large min boundary succeeds",
+			fieldType: reflect.TypeOf(int64(0)),
+			tag:       "SIMPLEENV_TEST_INT64_MIN_BOUNDARY;min=9007199254740993",
+			envValue:  strPtr("9007199254740993"),
+			wantValue: int64(9007199254740993),
+		},
+		{
+			name:        "uint64 max is compared exactly",
+			fieldType:   reflect.TypeOf(uint64(0)),
+			tag:         "SIMPLEENV_TEST_UINT64_MAX_PRECISE;max=18446744073709551614",
+			envValue:    strPtr("18446744073709551615"),
+			wantErr:     true,
+			errContains: []string{"a value <= 18446744073709551614"},
+		},
+		{
+			name:        "gt rejects the bound itself",
+			fieldType:   reflect.TypeOf(int(0)),
+			tag:         "SIMPLEENV_TEST_GT_BOUND;gt=0",
+			envValue:    strPtr("0"),
+			wantErr:     true,
+			errContains: []string{"a value > 0"},
+		},
+		{
+			name:      "gt accepts values above the bound",
+			fieldType: reflect.TypeOf(int(0)),
+			tag:       "SIMPLEENV_TEST_GT_ABOVE;gt=0",
+			envValue:  strPtr("1"),
+			wantValue: 1,
+		},
+		{
+			name:      "gte accepts the bound",
+			fieldType: reflect.TypeOf(uint(0)),
+			tag:       "SIMPLEENV_TEST_GTE_BOUND;gte=2",
+			envValue:  strPtr("2"),
+			wantValue: uint(2),
+		},
+		{
+			name:        "lt rejects the bound itself",
+			fieldType:   reflect.TypeOf(float64(0)),
+			tag:         "SIMPLEENV_TEST_LT_BOUND;lt=1",
+			envValue:    strPtr("1"),
+			wantErr:     true,
+			errContains: []string{"a value < 1"},
+		},
+		{
+			name:      "lte accepts the bound",
+			fieldType: reflect.TypeOf(float64(0)),
+			tag:       "SIMPLEENV_TEST_LTE_BOUND;lte=1",
+			envValue:  strPtr("1"),
+			wantValue: 1.0,
+		},
+		{
+			name:        "gt is compared exactly for large integers",
+			fieldType:   reflect.TypeOf(int64(0)),
+			tag:         "SIMPLEENV_TEST_GT_PRECISE;gt=9007199254740993",
+			envValue:    strPtr("9007199254740993"),
+			wantErr:     true,
+			errContains: []string{"a value > 9007199254740993"},
+		},
+		{
+			name:        "lt on durations",
+			fieldType:   reflect.TypeOf(time.Duration(0)),
+			tag:         "SIMPLEENV_TEST_LT_DURATION;lt=1m",
+			envValue:    strPtr("60s"),
+			wantErr:     true,
+			errContains: []string{"a value < 1m"},
+		},
+		{
+			name:        "gt with invalid bound is a tag error",
+			fieldType:   reflect.TypeOf(int(0)),
+			tag:         "SIMPLEENV_TEST_GT_INVALID;gt=abc",
+			envValue:    strPtr("1"),
+			wantErr:     true,
+			errContains: []string{`"gt=abc" must be a valid number`},
+		},
+		{
+			name:        "min applies to hex literals",
+			fieldType:   reflect.TypeOf(int(0)),
+			tag:         "SIMPLEENV_TEST_HEX_MIN;min=32",
+			envValue:    strPtr("0x1F"),
+			wantErr:     true,
+			errContains: []string{`got "0x1F"`, "a value >= 32"},
+		},
+		{
+			name:      "multipleof applies to binary literals",
+			fieldType: reflect.TypeOf(uint(0)),
+			tag:       "SIMPLEENV_TEST_BINARY_MULTIPLEOF;multipleof=4",
+			envValue:  strPtr("0b1000"),
+			wantValue: uint(8),
+		},
+		{
+			name:        "hex literal out of range",
+			fieldType:   reflect.TypeOf(uint8(0)),
+			tag:         "SIMPLEENV_TEST_HEX_RANGE",
+			envValue:    strPtr("0x100"),
+			wantErr:     true,
+			errContains: []string{"a valid uint8"},
+		},
+		{
+			name:      "multipleof accepts multiples",
+			fieldType: reflect.TypeOf(int(0)),
+			tag:       "SIMPLEENV_TEST_MULTIPLEOF_OK;multipleof=16",
+			envValue:  strPtr("64"),
+			wantValue: 64,
+		},
+		{
+			name:      "multipleof accepts negative multiples",
+			fieldType: reflect.TypeOf(int(0)),
+			tag:       "SIMPLEENV_TEST_MULTIPLEOF_NEGATIVE;multipleof=16",
+			envValue:  strPtr("-32"),
+			wantValue: -32,
+		},
+		{
+			name:        "multipleof rejects other values",
+			fieldType:   reflect.TypeOf(uint32(0)),
+			tag:         "SIMPLEENV_TEST_MULTIPLEOF_FAIL;multipleof=16",
+			envValue:    strPtr("20"),
+			wantErr:     true,
+			errContains: []string{`got "20"`, "expected a multiple of 16"},
+		},
+		{
+			name:        "multipleof must be positive",
+			fieldType:   reflect.TypeOf(int(0)),
+			tag:         "SIMPLEENV_TEST_MULTIPLEOF_ZERO;multipleof=0",
+			envValue:    strPtr("16"),
+			wantErr:     true,
+			errContains: []string{`"multipleof=0" must be a positive integer`},
+		},
+		{
+			name:        "multipleof on float is invalid",
+			fieldType:   reflect.TypeOf(float64(0)),
+			tag:         "SIMPLEENV_TEST_MULTIPLEOF_FLOAT;multipleof=2",
+			envValue:    strPtr("4"),
+			wantErr:     true,
+			errContains: []string{"multipleof is only supported for integer types"},
+		},
+		{
+			name:      "float field keeps float comparison",
+			fieldType: reflect.TypeOf(float64(0)),
+			tag:       "SIMPLEENV_TEST_FLOAT_MIN;min=0.5",
+			envValue:  strPtr("0.75"),
+			wantValue: 0.75,
+		},
+		{
+			name:        "min does not trim whitespace automatically",
+			fieldType:   reflect.TypeOf(int(0)),
+			tag:         "SIMPLEENV_TEST_MIN_WHITESPACE;min=1",
+			envValue:    strPtr(" 2 "),
+			wantErr:     true,
+			errContains: []string{"numeric value for min comparison"},
+		},
+		{
+			name:        "invalid bool returns error",
+			fieldType:   reflect.TypeOf(true),
+			tag:         "SIMPLEENV_TEST_BOOL_INVALID",
+			envValue:    strPtr("maybe"),
+			wantErr:     true,
+			errContains: []string{`field "Value"`, `got "maybe"`, "a valid bool"},
+		},
+		{
+			name:        "bool-like words beyond yes/no/on/off return error",
+			fieldType:   reflect.TypeOf(true),
+			tag:         "SIMPLEENV_TEST_BOOL_ENABLED",
+			envValue:    strPtr("enabled"),
+			wantErr:     true,
+			errContains: []string{`got "enabled"`, "a valid bool"},
+		},
+		{
+			name:        "int32 overflow returns range error",
+			fieldType:   reflect.TypeOf(int32(0)),
+			tag:         "SIMPLEENV_TEST_INT32_OVERFLOW",
+			envValue:    strPtr("40000000000"),
+			wantErr:     true,
+			errContains: []string{"a valid int32 within range [-2147483648, 2147483647]"},
+		},
+		{
+			name:        "negative uint returns non-negative error",
+			fieldType:   reflect.TypeOf(uint(0)),
+			tag:         "SIMPLEENV_TEST_UINT_NEGATIVE",
+			envValue:    strPtr("-1"),
+			wantErr:     true,
+			errContains: []string{"non-negative integer"},
+		},
+		{
+			name:        "uint8 overflow returns range error",
+			fieldType:   reflect.TypeOf(uint8(0)),
+			tag:         "SIMPLEENV_TEST_UINT8_OVERFLOW",
+			envValue:    strPtr("256"),
+			wantErr:     true,
+			errContains: []string{"a valid uint8 within range [0, 255]"},
+		},
+		{
+			name:        "float32 overflow returns range error",
+			fieldType:   reflect.TypeOf(float32(0)),
+			tag:         "SIMPLEENV_TEST_FLOAT32_OVERFLOW",
+			envValue:    strPtr("1e39"),
+			wantErr:     true,
+			errContains: []string{"a valid float32 within range"},
+		},
+		{
+			name:      "string slice splits and trims elements",
+			fieldType: reflect.TypeOf([]string{}),
+			tag:       "SIMPLEENV_TEST_SLICE",
+			envValue:  strPtr("a.com, b.com ,c.com"),
+			wantValue: []string{"a.com", "b.com", "c.com"},
+		},
+		{
+			name:      "string slice with custom separator",
+			fieldType: reflect.TypeOf([]string{}),
+			tag:       "SIMPLEENV_TEST_SLICE_SEP;sep=|",
+			envValue:  strPtr("a,b|c"),
+			wantValue: []string{"a,b", "c"},
+		},
+		{
+			name:      "string slice with allowempty yields empty slice",
+			fieldType: reflect.TypeOf([]string{}),
+			tag:       "SIMPLEENV_TEST_SLICE_EMPTY;optional;allowempty",
+			envValue:  strPtr(""),
+			wantValue: []string(nil),
+		},
+		{
+			name:        "sep on string is invalid",
+			fieldType:   reflect.TypeOf(""),
+			tag:         "SIMPLEENV_TEST_SEP_STRING;sep=|",
+			envValue:    strPtr("a|b"),
+			wantErr:     true,
+			errContains: []string{"sep is only supported"},
+		},
+		{
+			name:        "error message includes field env and expected",
+			fieldType:   reflect.TypeOf(int(0)),
+			tag:         "SIMPLEENV_TEST_ERROR_SHAPE;min=1",
+			envValue:    strPtr("abc"),
+			wantErr:     true,
+			errContains: []string{`field "Value"`, `ENV["SIMPLEENV_TEST_ERROR_SHAPE"]`, "expected"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := loadSingleField(t, tt.fieldType, tt.tag, tt.envValue)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				for _, contains := range tt.errContains {
+					if !strings.Contains(err.Error(), contains) {
+						t.Fatalf("expected error to contain %q, got %q", contains, err.Error())
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(value.Interface(), tt.wantValue) {
+				t.Fatalf("unexpected value: got %#v, want %#v", value.Interface(), tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestLoadWritesNothingToStdout(t *testing.T) {
+	type cfg struct {
+		Name string `env:"SIMPLEENV_TEST_SILENT_NAME"`
+	}
+
+	t.Setenv("SIMPLEENV_TEST_SILENT_NAME", "app")
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	t.Cleanup(func() { os.Stdout = stdout })
+
+	var c cfg
+	loadErr := Load(&c)
+	_ = w.Close()
+	os.Stdout = stdout
+
+	output, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read stdout: %v", err)
+	}
+	if loadErr != nil {
+		t.Fatalf("expected no error, got %v", loadErr)
+	}
+	if len(output) != 0 {
+		t.Fatalf("expected Load to write nothing to stdout, got %q", output)
+	}
+}
+
+func TestLoadAs(t *testing.T) {
+	type cfg struct {
+		Port int `env:"SIMPLEENV_TEST_LOAD_AS_PORT"`
+	}
+
+	t.Run("returns loaded struct", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_LOAD_AS_PORT", "8080")
+
+		c, err := LoadAs[cfg]()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Port != 8080 {
+			t.Fatalf("expected Port 8080, got %d", c.Port)
+		}
+	})
+
+	t.Run("returns nil on error", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_LOAD_AS_PORT")
+
+		c, err := LoadAs[cfg]()
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if c != nil {
+			t.Fatalf("expected nil config, got %+v", c)
+		}
+	})
+
+	t.Run("non-struct type returns input error", func(t *testing.T) {
+		_, err := LoadAs[int]()
+		if err == nil || !strings.Contains(err.Error(), "invalid Load input") {
+			t.Fatalf("expected input validation error, got %v", err)
+		}
+	})
+}
+
+func TestMustLoad(t *testing.T) {
+	type cfg struct {
+		Port int `env:"SIMPLEENV_TEST_MUST_PORT"`
+	}
+
+	mustPanic := func(t *testing.T, contains string, fn func()) {
+		t.Helper()
+
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Fatal("expected panic, got none")
+			}
+			err, ok := r.(error)
+			if !ok {
+				t.Fatalf("expected panic with error, got %#v", r)
+			}
+			if !strings.Contains(err.Error(), contains) {
+				t.Fatalf("expected panic error to contain %q, got %q", contains, err.Error())
+			}
+		}()
+
+		fn()
+	}
+
+	t.Run("MustLoad loads valid config", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_MUST_PORT", "8080")
+
+		var c cfg
+		MustLoad(&c)
+		if c.Port != 8080 {
+			t.Fatalf("expected Port 8080, got %d", c.Port)
+		}
+	})
+
+	t.Run("MustLoad panics with the load error", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_MUST_PORT", "abc")
+
+		var c cfg
+		mustPanic(t, `ENV["SIMPLEENV_TEST_MUST_PORT"]: got "abc", expected a valid int`, func() { MustLoad(&c) })
+	})
+
+	t.Run("MustLoadAs returns loaded struct", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_MUST_PORT", "9090")
+
+		c := MustLoadAs[cfg]()
+		if c.Port != 9090 {
+			t.Fatalf("expected Port 9090, got %d", c.Port)
+		}
+	})
+
+	t.Run("MustLoadAs panics with the load error", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_MUST_PORT")
+
+		mustPanic(t, "<unset>", func() { MustLoadAs[cfg]() })
+	})
+}
+
+func TestLoadFrom(t *testing.T) {
+	type cfg struct {
+		Name    string `env:"SIMPLEENV_TEST_FROM_NAME"`
+		Port    int    `env:"SIMPLEENV_TEST_FROM_PORT;min=1"`
+		Verbose bool   `env:"SIMPLEENV_TEST_FROM_VERBOSE;optional"`
+	}
+
+	t.Run("reads values from map", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_FROM_NAME", "from-env")
+
+		var c cfg
+		err := LoadFrom(&c, map[string]string{
+			"SIMPLEENV_TEST_FROM_NAME": "from-map",
+			"SIMPLEENV_TEST_FROM_PORT": "9000",
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Name != "from-map" || c.Port != 9000 || c.Verbose {
+			t.Fatalf("unexpected config: %+v", c)
+		}
+	})
+
+	t.Run("ignores process environment", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_FROM_NAME", "from-env")
+		t.Setenv("SIMPLEENV_TEST_FROM_PORT", "9000")
+
+		var c cfg
+		err := LoadFrom(&c, map[string]string{})
+		if err == nil || !strings.Contains(err.Error(), "<unset>") {
+			t.Fatalf("expected missing value error, got %v", err)
+		}
+	})
+
+	t.Run("validates map values", func(t *testing.T) {
+		var c cfg
+		err := LoadFrom(&c, map[string]string{
+			"SIMPLEENV_TEST_FROM_NAME": "app",
+			"SIMPLEENV_TEST_FROM_PORT": "0",
+		})
+		if err == nil || !strings.Contains(err.Error(), "a value >= 1") {
+			t.Fatalf("expected min error, got %v", err)
+		}
+	})
+}
+
+func TestLoadAliases(t *testing.T) {
+	type dbConfig struct {
+		Host string `env:"HOST;alias=HOSTNAME"`
+	}
+
+	type cfg struct {
+		Port int      `env:"PORT;alias=HTTP_PORT, SERVICE_PORT;min=1"`
+		DB   dbConfig `env:";prefix=DB_"`
+	}
+
+	tests := []struct {
+		name        string
+		source      MapSource
+		want        cfg
+		errContains []string
+	}{
+		{
+			name:   "primary key wins",
+			source: MapSource{"PORT": "1", "HTTP_PORT": "2", "SERVICE_PORT": "3", "DB_HOST": "db"},
+			want:   cfg{Port: 1, DB: dbConfig{Host: "db"}},
+		},
+		{
+			name:   "aliases are tried in order",
+			source: MapSource{"HTTP_PORT": "2", "SERVICE_PORT": "3", "DB_HOST": "db"},
+			want:   cfg{Port: 2, DB: dbConfig{Host: "db"}},
+		},
+		{
+			name:   "last alias",
+			source: MapSource{"SERVICE_PORT": "3", "DB_HOST": "db"},
+			want:   cfg{Port: 3, DB: dbConfig{Host: "db"}},
+		},
+		{
+			name:   "nested prefix applies to aliases",
+			source: MapSource{"PORT": "1", "DB_HOSTNAME": "db"},
+			want:   cfg{Port: 1, DB: dbConfig{Host: "db"}},
+		},
+		{
+			name:        "errors name the alias that was used",
+			source:      MapSource{"SERVICE_PORT": "0", "DB_HOST": "db"},
+			errContains: []string{`ENV["SERVICE_PORT"]`, "a value >= 1"},
+		},
+		{
+			name:        "missing errors name the primary key",
+			source:      MapSource{"DB_HOST": "db"},
+			errContains: []string{`ENV["PORT"]`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c cfg
+			err := LoadFrom(&c, tt.source)
+			if len(tt.errContains) > 0 {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				for _, want := range tt.errContains {
+					if !strings.Contains(err.Error(), want) {
+						t.Fatalf("expected error to contain %q, got %q", want, err.Error())
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(c, tt.want) {
+				t.Fatalf("unexpected config: got %+v, want %+v", c, tt.want)
+			}
+		})
+	}
+
+	t.Run("schema uses aliases", func(t *testing.T) {
+		schema, err := Compile[cfg](WithSource(MapSource{"HTTP_PORT": "2", "DB_HOSTNAME": "db"}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		for range 2 {
+			var c cfg
+			if err := schema.Load(&c); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if c.Port != 2 || c.DB.Host != "db" {
+				t.Fatalf("unexpected config: %+v", c)
+			}
+		}
+	})
+
+	t.Run("empty alias is invalid", func(t *testing.T) {
+		type invalid struct {
+			Port int `env:"PORT;alias=HTTP_PORT,"`
+		}
+
+		err := LoadFrom(&invalid{}, MapSource{"PORT": "1"})
+		if !errors.Is(err, ErrInvalidTag) {
+			t.Fatalf("expected errors.Is(err, ErrInvalidTag), got %v", err)
+		}
+	})
+}
+
+type windowConfig struct {
+	Start int `env:"SIMPLEENV_TEST_HOOK_START"`
+	End   int `env:"SIMPLEENV_TEST_HOOK_END"`
+
+	calls int
+}
+
+var errWindowOrder = errors.New("start must be before end")
+
+func (c *windowConfig) Validate() error {
+	c.calls++
+	if c.Start >= c.End {
+		return errWindowOrder
+	}
+
+	return nil
+}
+
+func TestLoadValidatorHook(t *testing.T) {
+	t.Run("hook runs once after fields are loaded", func(t *testing.T) {
+		var c windowConfig
+		err := LoadFrom(&c, map[string]string{"SIMPLEENV_TEST_HOOK_START": "1", "SIMPLEENV_TEST_HOOK_END": "2"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.calls != 1 {
+			t.Fatalf("expected hook to run once, ran %d times", c.calls)
+		}
+	})
+
+	t.Run("hook error is returned unchanged", func(t *testing.T) {
+		var c windowConfig
+		err := LoadFrom(&c, map[string]string{"SIMPLEENV_TEST_HOOK_START": "2", "SIMPLEENV_TEST_HOOK_END": "1"})
+		if err != errWindowOrder {
+			t.Fatalf("expected hook error, got %v", err)
+		}
+	})
+
+	t.Run("hook is skipped when a field fails", func(t *testing.T) {
+		var c windowConfig
+		err := LoadFrom(&c, map[string]string{"SIMPLEENV_TEST_HOOK_START": "x", "SIMPLEENV_TEST_HOOK_END": "1"})
+		if !errors.Is(err, ErrParse) || c.calls != 0 {
+			t.Fatalf("expected parse error without running the hook, got %v (calls %d)", err, c.calls)
+		}
+
+		c = windowConfig{}
+		t.Setenv("SIMPLEENV_TEST_HOOK_START", "x")
+		t.Setenv("SIMPLEENV_TEST_HOOK_END", "1")
+		if err := LoadAll(&c); !errors.Is(err, ErrParse) || c.calls != 0 {
+			t.Fatalf("expected LoadAll parse error without running the hook, got %v (calls %d)", err, c.calls)
+		}
+	})
+
+	t.Run("schema runs the hook", func(t *testing.T) {
+		schema, err := Compile[windowConfig](WithSource(MapSource{"SIMPLEENV_TEST_HOOK_START": "2", "SIMPLEENV_TEST_HOOK_END": "1"}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		var c windowConfig
+		if err := schema.Load(&c); err != errWindowOrder {
+			t.Fatalf("expected hook error, got %v", err)
+		}
+	})
+
+	t.Run("Validate does not run the hook", func(t *testing.T) {
+		c := windowConfig{Start: 2, End: 1}
+		if err := Validate(&c); err != nil || c.calls != 0 {
+			t.Fatalf("expected Validate to skip the hook, got %v (calls %d)", err, c.calls)
+		}
+	})
+}
+
+func TestLoadWithEmptyAsUnset(t *testing.T) {
+	type cfg struct {
+		Host   string `env:"SIMPLEENV_TEST_EMPTY_UNSET_HOST"`
+		Region string `env:"SIMPLEENV_TEST_EMPTY_UNSET_REGION;optional;allowempty"`
+		Port   int    `env:"SIMPLEENV_TEST_EMPTY_UNSET_PORT;default=8080"`
+		Name   string `env:"SIMPLEENV_TEST_EMPTY_UNSET_NAME;alias=SIMPLEENV_TEST_EMPTY_UNSET_APP"`
+	}
+
+	values := MapSource{
+		"SIMPLEENV_TEST_EMPTY_UNSET_HOST":   "db.local",
+		"SIMPLEENV_TEST_EMPTY_UNSET_REGION": "",
+		"SIMPLEENV_TEST_EMPTY_UNSET_PORT":   "",
+		"SIMPLEENV_TEST_EMPTY_UNSET_NAME":   "",
+		"SIMPLEENV_TEST_EMPTY_UNSET_APP":    "billing",
+	}
+
+	t.Run("empty values count as unset", func(t *testing.T) {
+		c := cfg{Region: "eu-west-1"}
+		if err := LoadWithOptions(&c, WithSource(values), WithEmptyAsUnset()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Host != "db.local" || c.Region != "eu-west-1" || c.Port != 8080 || c.Name != "billing" {
+			t.Fatalf("unexpected config: %+v", c)
+		}
+	})
+
+	t.Run("empty required value is missing", func(t *testing.T) {
+		empty := maps.Clone(values)
+		empty["SIMPLEENV_TEST_EMPTY_UNSET_HOST"] = ""
+
+		err := LoadWithOptions(&cfg{}, WithSource(empty), WithEmptyAsUnset())
+		if !errors.Is(err, ErrMissingRequired) || !strings.Contains(err.Error(), "SIMPLEENV_TEST_EMPTY_UNSET_HOST") {
+			t.Fatalf("expected missing Host, got %v", err)
+		}
+	})
+
+	t.Run("empty values are present by default", func(t *testing.T) {
+		err := LoadWithOptions(&cfg{}, WithSource(values))
+		if !errors.Is(err, ErrConstraint) || !strings.Contains(err.Error(), "a non-empty value") {
+			t.Fatalf("expected empty Port to be rejected, got %v", err)
+		}
+	})
+}
+
+func TestLoadWithOptionalByDefault(t *testing.T) {
+	type cfg struct {
+		Host    string            `env:"SIMPLEENV_TEST_OPTDEFAULT_HOST"`
+		Port    int               `env:"SIMPLEENV_TEST_OPTDEFAULT_PORT;default=8080"`
+		Labels  map[string]string `env:"SIMPLEENV_TEST_OPTDEFAULT_LABEL_;collect"`
+		Token   string            `env:"SIMPLEENV_TEST_OPTDEFAULT_TOKEN;required"`
+		Retries int               `env:"SIMPLEENV_TEST_OPTDEFAULT_RETRIES;min=1"`
+	}
+
+	t.Run("untagged fields become optional", func(t *testing.T) {
+		c := cfg{Host: "kept"}
+		err := LoadWithOptions(&c, WithOptionalByDefault(), WithSource(MapSource{"SIMPLEENV_TEST_OPTDEFAULT_TOKEN": "t"}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := cfg{Host: "kept", Port: 8080, Token: "t"}
+		if !reflect.DeepEqual(c, want) {
+			t.Fatalf("unexpected config:\ngot:  %+v\nwant: %+v", c, want)
+		}
+	})
+
+	t.Run("required fields stay required", func(t *testing.T) {
+		err := LoadWithOptions(&cfg{}, WithOptionalByDefault(), WithSource(MapSource{}))
+		if !errors.Is(err, ErrMissingRequired) || !strings.Contains(err.Error(), "SIMPLEENV_TEST_OPTDEFAULT_TOKEN") {
+			t.Fatalf("expected missing Token error, got %v", err)
+		}
+	})
+
+	t.Run("present values are still validated", func(t *testing.T) {
+		source := MapSource{"SIMPLEENV_TEST_OPTDEFAULT_TOKEN": "t", "SIMPLEENV_TEST_OPTDEFAULT_RETRIES": "0"}
+		err := LoadWithOptions(&cfg{}, WithOptionalByDefault(), WithSource(source))
+		if !errors.Is(err, ErrConstraint) {
+			t.Fatalf("expected constraint error, got %v", err)
+		}
+	})
+
+	t.Run("schema applies the option", func(t *testing.T) {
+		schema, err := Compile[cfg](WithOptionalByDefault(), WithSource(MapSource{"SIMPLEENV_TEST_OPTDEFAULT_TOKEN": "t"}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := schema.Load(&cfg{}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestLoadWithCaseInsensitiveKeys(t *testing.T) {
+	type cfg struct {
+		Port  int    `env:"APP_PORT"`
+		Host  string `env:"APP_HOST;alias=HOSTNAME"`
+		Debug bool   `env:"APP_DEBUG;optional"`
+	}
+
+	t.Run("falls back to a case-insensitive match", func(t *testing.T) {
+		var c cfg
+		err := LoadWithOptions(&c, WithCaseInsensitiveKeys(), WithSource(MapSource{"app_port": "8080", "hostname": "db.local"}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Port != 8080 || c.Host != "db.local" || c.Debug {
+			t.Fatalf("unexpected config: %+v", c)
+		}
+	})
+
+	t.Run("exact matches win", func(t *testing.T) {
+		var c cfg
+		err := LoadWithOptions(&c, WithCaseInsensitiveKeys(), WithSource(MapSource{"APP_PORT": "1", "app_port": "2", "App_Port": "3", "APP_HOST": "h"}))
+		if err != nil || c.Port != 1 {
+			t.Fatalf("expected the exact key to be used, got %+v (%v)", c, err)
+		}
+	})
+
+	t.Run("errors name the key that matched", func(t *testing.T) {
+		err := LoadWithOptions(&cfg{}, WithCaseInsensitiveKeys(), WithSource(MapSource{"App_Port": "http", "APP_HOST": "h"}))
+		if err == nil || !strings.Contains(err.Error(), `ENV["App_Port"]`) {
+			t.Fatalf("expected parse error for App_Port, got %v", err)
+		}
+	})
+
+	t.Run("ambiguous matches are an error", func(t *testing.T) {
+		err := LoadWithOptions(&cfg{}, WithCaseInsensitiveKeys(), WithSource(MapSource{"app_port": "1", "App_Port": "2", "APP_HOST": "h"}))
+		if want := `field "Port" (ENV["APP_PORT"]) matches several keys ignoring case: "App_Port", "app_port"`; err == nil || err.Error() != want {
+			t.Fatalf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("keys stay case-sensitive by default", func(t *testing.T) {
+		err := LoadWithOptions(&cfg{}, WithSource(MapSource{"app_port": "8080", "APP_HOST": "h"}))
+		if !errors.Is(err, ErrMissingRequired) {
+			t.Fatalf("expected missing required error, got %v", err)
+		}
+	})
+
+	t.Run("requires a KeySource", func(t *testing.T) {
+		source := &recordingSource{values: map[string]string{"app_port": "8080"}}
+		err := LoadWithOptions(&cfg{}, WithCaseInsensitiveKeys(), WithSource(source))
+		if err == nil || !strings.Contains(err.Error(), "requires a Source that implements KeySource") {
+			t.Fatalf("expected KeySource error, got %v", err)
+		}
+	})
+
+	t.Run("matched keys count as known", func(t *testing.T) {
+		err := LoadWithOptions(&cfg{}, WithCaseInsensitiveKeys(), WithExpectedPrefix("app_"), WithSource(MapSource{"app_port": "8080", "app_host": "h"}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestLoadSecretFiles(t *testing.T) {
+	type cfg struct {
+		Password string `env:"SIMPLEENV_TEST_SECRET_PASSWORD"`
+	}
+
+	writeSecret := func(t *testing.T, content string) string {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "secret")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write secret file: %v", err)
+		}
+		return path
+	}
+
+	t.Run("reads value from KEY_FILE", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_SECRET_PASSWORD")
+		t.Setenv("SIMPLEENV_TEST_SECRET_PASSWORD_FILE", writeSecret(t, "s3cret\n"))
+
+		var c cfg
+		err := LoadWithOptions(&c, WithSecretFiles())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Password != "s3cret" {
+			t.Fatalf("expected trimmed secret, got %q", c.Password)
+		}
+	})
+
+	t.Run("inline value wins over KEY_FILE", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_SECRET_PASSWORD", "inline")
+		t.Setenv("SIMPLEENV_TEST_SECRET_PASSWORD_FILE", writeSecret(t, "from-file"))
+
+		var c cfg
+		err := LoadWithOptions(&c, WithSecretFiles())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Password != "inline" {
+			t.Fatalf("expected inline value, got %q", c.Password)
+		}
+	})
+
+	t.Run("strict mode rejects KEY and KEY_FILE together", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_SECRET_PASSWORD", "inline")
+		t.Setenv("SIMPLEENV_TEST_SECRET_PASSWORD_FILE", writeSecret(t, "from-file"))
+
+		var c cfg
+		err := LoadWithOptions(&c, WithStrictSecretFiles())
+		want := `field "Password" is set by both ENV["SIMPLEENV_TEST_SECRET_PASSWORD"] and ENV["SIMPLEENV_TEST_SECRET_PASSWORD_FILE"]; unset one of them`
+		if err == nil || err.Error() != want {
+			t.Fatalf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("strict mode accepts either key alone", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_SECRET_PASSWORD")
+		t.Setenv("SIMPLEENV_TEST_SECRET_PASSWORD_FILE", writeSecret(t, "from-file"))
+
+		var c cfg
+		if err := LoadWithOptions(&c, WithStrictSecretFiles()); err != nil || c.Password != "from-file" {
+			t.Fatalf("expected file value, got %q (%v)", c.Password, err)
+		}
+
+		unsetEnv(t, "SIMPLEENV_TEST_SECRET_PASSWORD_FILE")
+		t.Setenv("SIMPLEENV_TEST_SECRET_PASSWORD", "inline")
+		if err := LoadWithOptions(&c, WithStrictSecretFiles()); err != nil || c.Password != "inline" {
+			t.Fatalf("expected inline value, got %q (%v)", c.Password, err)
+		}
+	})
+
+	t.Run("KEY_FILE is ignored unless enabled", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_SECRET_PASSWORD")
+		t.Setenv("SIMPLEENV_TEST_SECRET_PASSWORD_FILE", writeSecret(t, "s3cret"))
+
+		var c cfg
+		err := Load(&c)
+		if err == nil || !strings.Contains(err.Error(), "<unset>") {
+			t.Fatalf("expected missing value error, got %v", err)
+		}
+	})
+
+	t.Run("missing file returns error", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_SECRET_PASSWORD")
+		t.Setenv("SIMPLEENV_TEST_SECRET_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+
+		var c cfg
+		err := LoadWithOptions(&c, WithSecretFiles())
+		if err == nil || !strings.Contains(err.Error(), `ENV["SIMPLEENV_TEST_SECRET_PASSWORD_FILE"]`) {
+			t.Fatalf("expected file read error, got %v", err)
+		}
+	})
+
+	t.Run("missing file leaves optional field unset", func(t *testing.T) {
+		type optionalCfg struct {
+			Password string `env:"SIMPLEENV_TEST_SECRET_PASSWORD;optional"`
+			Token    string `env:"SIMPLEENV_TEST_SECRET_TOKEN;default=dev-token"`
+		}
+
+		unsetEnv(t, "SIMPLEENV_TEST_SECRET_PASSWORD")
+		unsetEnv(t, "SIMPLEENV_TEST_SECRET_TOKEN")
+		t.Setenv("SIMPLEENV_TEST_SECRET_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+		t.Setenv("SIMPLEENV_TEST_SECRET_TOKEN_FILE", filepath.Join(t.TempDir(), "missing"))
+
+		var c optionalCfg
+		err := LoadWithOptions(&c, WithSecretFiles())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Password != "" || c.Token != "dev-token" {
+			t.Fatalf("expected zero password and default token, got %q and %q", c.Password, c.Token)
+		}
+	})
+
+	t.Run("unreadable file for optional field returns error", func(t *testing.T) {
+		type optionalCfg struct {
+			Password string `env:"SIMPLEENV_TEST_SECRET_PASSWORD;optional"`
+		}
+
+		unsetEnv(t, "SIMPLEENV_TEST_SECRET_PASSWORD")
+		t.Setenv("SIMPLEENV_TEST_SECRET_PASSWORD_FILE", t.TempDir())
+
+		var c optionalCfg
+		err := LoadWithOptions(&c, WithSecretFiles())
+		if err == nil || !strings.Contains(err.Error(), `ENV["SIMPLEENV_TEST_SECRET_PASSWORD_FILE"]`) {
+			t.Fatalf("expected file read error, got %v", err)
+		}
+	})
+}
+
+func TestLoadWithTrimSpace(t *testing.T) {
+	type cfg struct {
+		Mode    string        `env:"MODE;oneof=dev,prod"`
+		Port    int           `env:"PORT;min=1"`
+		Debug   bool          `env:"DEBUG"`
+		Timeout time.Duration `env:"TIMEOUT"`
+		Hosts   []string      `env:"HOSTS"`
+	}
+
+	source := MapSource{
+		"MODE":    " prod\n",
+		"PORT":    "8080 ",
+		"DEBUG":   "\ttrue",
+		"TIMEOUT": " 5s ",
+		"HOSTS":   " a, b ",
+	}
+
+	var c cfg
+	if err := LoadWithOptions(&c, WithSource(source), WithTrimSpace()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := cfg{Mode: "prod", Port: 8080, Debug: true, Timeout: 5 * time.Second, Hosts: []string{"a", "b"}}
+	if !reflect.DeepEqual(c, want) {
+		t.Fatalf("unexpected config: got %+v, want %+v", c, want)
+	}
+
+	var untrimmed cfg
+	err := LoadWithOptions(&untrimmed, WithSource(source))
+	if !errors.Is(err, ErrConstraint) {
+		t.Fatalf("expected untrimmed values to fail by default, got %v", err)
+	}
+
+	err = LoadWithOptions(&untrimmed, WithSource(MapSource{"MODE": "  ", "PORT": "1", "DEBUG": "1", "TIMEOUT": "1s", "HOSTS": "a"}), WithTrimSpace())
+	if err == nil || !strings.Contains(err.Error(), "a non-empty value") {
+		t.Fatalf("expected whitespace-only value to be rejected as empty, got %v", err)
+	}
+}
+
+func TestLoadExpandVars(t *testing.T) {
+	type cfg struct {
+		Greeting string `env:"GREETING"`
+		Home     string `env:"HOME_DIR;default=/home/${USER}"`
+	}
+
+	t.Run("expands references through the source", func(t *testing.T) {
+		var c cfg
+		err := LoadWithOptions(&c, WithExpandVars(), WithSource(MapSource{
+			"GREETING": "Hello ${USER}, welcome to $APP",
+			"USER":     "ada",
+			"APP":      "simpleenv",
+		}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Greeting != "Hello ada, welcome to simpleenv" {
+			t.Fatalf("unexpected greeting: %q", c.Greeting)
+		}
+		if c.Home != "/home/ada" {
+			t.Fatalf("expected default to be expanded, got %q", c.Home)
+		}
+	})
+
+	t.Run("double dollars are literal", func(t *testing.T) {
+		var c cfg
+		err := LoadWithOptions(&c, WithStrictExpandVars(), WithSource(MapSource{
+			"GREETING": "costs $$5 for $$$USER",
+			"USER":     "ada",
+		}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Greeting != "costs $5 for $ada" {
+			t.Fatalf("unexpected greeting: %q", c.Greeting)
+		}
+	})
+
+	t.Run("undefined references expand to empty", func(t *testing.T) {
+		var c cfg
+		err := LoadWithOptions(&c, WithExpandVars(), WithSource(MapSource{
+			"GREETING": "Hello ${USER}!",
+		}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Greeting != "Hello !" {
+			t.Fatalf("unexpected greeting: %q", c.Greeting)
+		}
+	})
+
+	t.Run("strict expansion errors on undefined references", func(t *testing.T) {
+		var c cfg
+		err := LoadWithOptions(&c, WithStrictExpandVars(), WithSource(MapSource{
+			"GREETING": "Hello ${USER}!",
+		}))
+		if err == nil || !strings.Contains(err.Error(), `referenced variable "USER" to be set`) {
+			t.Fatalf("expected undefined reference error, got %v", err)
+		}
+	})
+
+	t.Run("values are not expanded by default", func(t *testing.T) {
+		var c cfg
+		err := LoadFrom(&c, map[string]string{
+			"GREETING": "pa$$word ${USER}",
+			"HOME_DIR": "/root",
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Greeting != "pa$$word ${USER}" {
+			t.Fatalf("expected raw value, got %q", c.Greeting)
+		}
+	})
+}
+
+func TestLoadAll(t *testing.T) {
+	type cfg struct {
+		Name        string `env:"SIMPLEENV_TEST_ALL_NAME"`
+		Port        int    `env:"SIMPLEENV_TEST_ALL_PORT;min=1"`
+		Environment string `env:"SIMPLEENV_TEST_ALL_ENVIRONMENT;oneof=dev,prod"`
+		Debug       bool   `env:"SIMPLEENV_TEST_ALL_DEBUG"`
+	}
+
+	t.Run("reports every invalid field", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_ALL_NAME")
+		t.Setenv("SIMPLEENV_TEST_ALL_PORT", "0")
+		t.Setenv("SIMPLEENV_TEST_ALL_ENVIRONMENT", "staging")
+		t.Setenv("SIMPLEENV_TEST_ALL_DEBUG", "true")
+
+		var c cfg
+		err := LoadAll(&c)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		lines := strings.Split(err.Error(), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("expected 3 error lines, got %d: %q", len(lines), err.Error())
+		}
+		for i, contains := range []string{`ENV["SIMPLEENV_TEST_ALL_NAME"]`, `ENV["SIMPLEENV_TEST_ALL_PORT"]`, `ENV["SIMPLEENV_TEST_ALL_ENVIRONMENT"]`} {
+			if !strings.Contains(lines[i], contains) {
+				t.Fatalf("expected line %d to contain %q, got %q", i, contains, lines[i])
+			}
+		}
+		if !c.Debug {
+			t.Fatal("expected valid fields to still be loaded")
+		}
+	})
+
+	t.Run("returns nil when every field is valid", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_ALL_NAME", "app")
+		t.Setenv("SIMPLEENV_TEST_ALL_PORT", "8080")
+		t.Setenv("SIMPLEENV_TEST_ALL_ENVIRONMENT", "dev")
+		t.Setenv("SIMPLEENV_TEST_ALL_DEBUG", "false")
+
+		var c cfg
+		err := LoadAll(&c)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("input validation still fails fast", func(t *testing.T) {
+		err := LoadAll(nil)
+		if err == nil || !strings.Contains(err.Error(), "invalid Load input") {
+			t.Fatalf("expected input validation error, got %v", err)
+		}
+	})
+}
+
+func TestLoadOrDefault(t *testing.T) {
+	type cfg struct {
+		Name  string `env:"SIMPLEENV_TEST_OR_DEFAULT_NAME"`
+		Port  int    `env:"SIMPLEENV_TEST_OR_DEFAULT_PORT;min=1"`
+		Debug bool   `env:"SIMPLEENV_TEST_OR_DEFAULT_DEBUG"`
+	}
+
+	t.Run("missing required fields become warnings", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_OR_DEFAULT_NAME")
+		unsetEnv(t, "SIMPLEENV_TEST_OR_DEFAULT_PORT")
+		t.Setenv("SIMPLEENV_TEST_OR_DEFAULT_DEBUG", "true")
+
+		c := cfg{Port: 8080}
+		warnings, err := LoadOrDefault(&c)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(warnings) != 2 {
+			t.Fatalf("expected 2 warnings, got %d: %v", len(warnings), warnings)
+		}
+		for i, key := range []string{"SIMPLEENV_TEST_OR_DEFAULT_NAME", "SIMPLEENV_TEST_OR_DEFAULT_PORT"} {
+			var fieldErr *FieldError
+			if !errors.As(warnings[i], &fieldErr) || fieldErr.EnvKey != key || !errors.Is(warnings[i], ErrMissingRequired) {
+				t.Fatalf("expected warning %d to be a missing-required error for %s, got %v", i, key, warnings[i])
+			}
+		}
+		if c.Name != "" || c.Port != 8080 || !c.Debug {
+			t.Fatalf("expected missing fields to keep their values, got %+v", c)
+		}
+	})
+
+	t.Run("invalid values are still fatal", func(t *testing.T) {
+		unsetEnv(t, "SIMPLEENV_TEST_OR_DEFAULT_NAME")
+		t.Setenv("SIMPLEENV_TEST_OR_DEFAULT_PORT", "0")
+		t.Setenv("SIMPLEENV_TEST_OR_DEFAULT_DEBUG", "maybe")
+
+		var c cfg
+		warnings, err := LoadOrDefault(&c)
+		if !errors.Is(err, ErrConstraint) || !strings.Contains(err.Error(), `ENV["SIMPLEENV_TEST_OR_DEFAULT_PORT"]`) {
+			t.Fatalf("expected constraint error for the port, got %v", err)
+		}
+		if len(warnings) != 1 {
+			t.Fatalf("expected the warning collected before the error, got %v", warnings)
+		}
+	})
+
+	t.Run("returns no warnings when every field is set", func(t *testing.T) {
+		warnings, err := LoadOrDefault(&cfg{}, WithSource(MapSource{
+			"SIMPLEENV_TEST_OR_DEFAULT_NAME":  "app",
+			"SIMPLEENV_TEST_OR_DEFAULT_PORT":  "8080",
+			"SIMPLEENV_TEST_OR_DEFAULT_DEBUG": "false",
+		}))
+		if err != nil || warnings != nil {
+			t.Fatalf("expected no warnings or error, got %v, %v", warnings, err)
+		}
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		if _, err := LoadOrDefault(cfg{}); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected input error, got %v", err)
+		}
+	})
+}
+
+func TestLoadDeprecatedKeys(t *testing.T) {
+	type cfg struct {
+		Port int    `env:"APP_PORT;alias=PORT;deprecated=use APP_PORT instead"`
+		Host string `env:"HOST;optional;default=localhost;deprecated=set APP_HOST instead"`
+	}
+
+	load := func(t *testing.T, source MapSource) []error {
+		t.Helper()
+
+		var warnings []error
+		var c cfg
+		err := LoadWithOptions(&c, WithSource(source), WithWarningHandler(func(warning error) {
+			warnings = append(warnings, warning)
+		}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		return warnings
+	}
+
+	t.Run("reading a deprecated key warns with the message verbatim", func(t *testing.T) {
+		warnings := load(t, MapSource{"PORT": "8080"})
+		if len(warnings) != 1 {
+			t.Fatalf("expected 1 warning, got %v", warnings)
+		}
+		if want := `field "Port" reads deprecated ENV["PORT"]: use APP_PORT instead`; warnings[0].Error() != want || !errors.Is(warnings[0], ErrDeprecatedKey) {
+			t.Fatalf("expected warning %q, got %v", want, warnings[0])
+		}
+	})
+
+	t.Run("new keys and defaults do not warn", func(t *testing.T) {
+		if warnings := load(t, MapSource{"APP_PORT": "8080"}); len(warnings) != 0 {
+			t.Fatalf("expected no warnings, got %v", warnings)
+		}
+	})
+
+	t.Run("fields without aliases warn on their own key", func(t *testing.T) {
+		warnings := load(t, MapSource{"APP_PORT": "8080", "HOST": "db"})
+		if len(warnings) != 1 || warnings[0].Error() != `field "Host" reads deprecated ENV["HOST"]: set APP_HOST instead` {
+			t.Fatalf("expected the host warning, got %v", warnings)
+		}
+	})
+
+	t.Run("warnings do not fail Load", func(t *testing.T) {
+		var c cfg
+		err := LoadWithOptions(&c, WithSource(MapSource{"PORT": "8080", "HOST": "db"}))
+		if err != nil || c.Port != 8080 || c.Host != "db" {
+			t.Fatalf("expected config to load, got %+v (%v)", c, err)
+		}
+	})
+
+	t.Run("LoadOrDefault returns deprecation warnings", func(t *testing.T) {
+		warnings, err := LoadOrDefault(&cfg{}, WithSource(MapSource{"HOST": "db"}))
+		if err != nil || len(warnings) != 2 || !errors.Is(warnings[0], ErrMissingRequired) || !errors.Is(warnings[1], ErrDeprecatedKey) {
+			t.Fatalf("expected missing and deprecated warnings, got %v (%v)", warnings, err)
+		}
+	})
+
+	t.Run("empty message is a tag error", func(t *testing.T) {
+		type badCfg struct {
+			Port int `env:"PORT;deprecated="`
+		}
+
+		err := LoadFrom(&badCfg{}, map[string]string{"PORT": "1"})
+		if !errors.Is(err, ErrInvalidTag) || !strings.Contains(err.Error(), "deprecated needs a message") {
+			t.Fatalf("expected tag error, got %v", err)
+		}
+	})
+}
+
+func TestLoadTagRules(t *testing.T) {
+	t.Run("field without env tag is skipped", func(t *testing.T) {
+		type cfg struct {
+			Name         string `env:"SIMPLEENV_TEST_WITH_TAG"`
+			DefaultValue string
+		}
+
+		t.Setenv("SIMPLEENV_TEST_WITH_TAG", "from-env")
+
+		var c cfg
+		err := Load(&c)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Name != "from-env" {
+			t.Fatalf("expected Name from env, got %q", c.Name)
+		}
+		if c.DefaultValue != "" {
+			t.Fatalf("expected untagged field zero value, got %q", c.DefaultValue)
+		}
+	})
+
+	t.Run("empty env tag value returns error", func(t *testing.T) {
+		type cfg struct {
+			NoKey string `env:""`
+		}
+
+		var c cfg
+		err := Load(&c)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+
+	t.Run("malformed tag returns error", func(t *testing.T) {
+		field := reflect.StructField{
+			Name: "NoKey",
+			Tag:  reflect.StructTag(`env:`),
+		}
+
+		_, err := parseEnvTag(field, "env", ";")
+		if err == nil {
+			t.Fatal("expected error for malformed env tag, got nil")
+		}
+	})
+}
+
+func TestSplitOptionList(t *testing.T) {
+	tests := []struct {
+		list string
+		want []string
+	}{
+		{list: "a,b,c", want: []string{"a", "b", "c"}},
+		{list: `a\,b,c`, want: []string{"a,b", "c"}},
+		{list: `a\b`, want: []string{`a\b`}},
+		{list: `trailing\`, want: []string{`trailing\`}},
+		{list: "", want: []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.list, func(t *testing.T) {
+			if got := splitOptionList(tt.list); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("splitOptionList(%q) = %q, want %q", tt.list, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadOneOfEscapedCommaTag(t *testing.T) {
+	type cfg struct {
+		Locale string `env:"SIMPLEENV_TEST_ONEOF_LOCALE;oneof=en\\,US,fr"`
+	}
+
+	t.Setenv("SIMPLEENV_TEST_ONEOF_LOCALE", "en,US")
+
+	var c cfg
+	if err := Load(&c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if c.Locale != "en,US" {
+		t.Fatalf("expected Locale %q, got %q", "en,US", c.Locale)
+	}
+}
+
+func TestLoadJSONValues(t *testing.T) {
+	type limits struct {
+		Burst int     `json:"burst"`
+		Rate  float64 `json:"rate"`
+	}
+
+	type cfg struct {
+		Flags   map[string]bool `env:"SIMPLEENV_TEST_JSON_FLAGS;json"`
+		Limits  limits          `env:"SIMPLEENV_TEST_JSON_LIMITS;json"`
+		Ports   []int           `env:"SIMPLEENV_TEST_JSON_PORTS;json"`
+		Regions *[]string       `env:"SIMPLEENV_TEST_JSON_REGIONS;json;optional"`
+		Hosts   []string        `env:"SIMPLEENV_TEST_JSON_HOSTS"`
+	}
+
+	t.Run("values are decoded", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_JSON_FLAGS", `{"beta":true,"legacy":false}`)
+		t.Setenv("SIMPLEENV_TEST_JSON_LIMITS", `{"burst":10,"rate":2.5}`)
+		t.Setenv("SIMPLEENV_TEST_JSON_PORTS", `[80,443]`)
+		t.Setenv("SIMPLEENV_TEST_JSON_REGIONS", `["eu","us"]`)
+		t.Setenv("SIMPLEENV_TEST_JSON_HOSTS", `["a","b"]`)
+
+		var c cfg
+		if err := Load(&c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := cfg{
+			Flags:   map[string]bool{"beta": true, "legacy": false},
+			Limits:  limits{Burst: 10, Rate: 2.5},
+			Ports:   []int{80, 443},
+			Regions: &[]string{"eu", "us"},
+			Hosts:   []string{`["a"`, `"b"]`},
+		}
+		if !reflect.DeepEqual(c, want) {
+			t.Fatalf("unexpected config:\ngot:  %+v\nwant: %+v", c, want)
+		}
+
+		if err := Validate(&c); err != nil {
+			t.Fatalf("expected loaded JSON config to validate, got %v", err)
+		}
+
+		out, err := Marshal(&c)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !strings.Contains(out, `SIMPLEENV_TEST_JSON_PORTS="[80,443]"`) {
+			t.Fatalf("expected JSON field to marshal as JSON, got:\n%s", out)
+		}
+	})
+
+	t.Run("invalid JSON names the field", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_JSON_FLAGS", `{"beta":true`)
+
+		var c cfg
+		err := Load(&c)
+		if !errors.Is(err, ErrParse) {
+			t.Fatalf("expected errors.Is(err, ErrParse), got %v", err)
+		}
+		for _, want := range []string{`field "Flags"`, "expected valid JSON for map[string]bool"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Fatalf("expected error to contain %q, got %q", want, err.Error())
+			}
+		}
+	})
+
+	t.Run("json cannot be combined with sep", func(t *testing.T) {
+		type invalid struct {
+			Hosts []string `env:"SIMPLEENV_TEST_JSON_SEP;json;sep=|"`
+		}
+
+		err := Load(&invalid{})
+		if !errors.Is(err, ErrInvalidTag) {
+			t.Fatalf("expected errors.Is(err, ErrInvalidTag), got %v", err)
+		}
+	})
+}
+
+func TestLoadCharValues(t *testing.T) {
+	type cfg struct {
+		Delim rune  `env:"SIMPLEENV_TEST_CHAR_DELIM;char;oneof=|,:"`
+		Quote byte  `env:"SIMPLEENV_TEST_CHAR_QUOTE;char"`
+		Mark  *rune `env:"SIMPLEENV_TEST_CHAR_MARK;char;optional"`
+		Count int32 `env:"SIMPLEENV_TEST_CHAR_COUNT"`
+	}
+
+	t.Run("values are stored as code points", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_CHAR_DELIM", "|")
+		t.Setenv("SIMPLEENV_TEST_CHAR_QUOTE", `"`)
+		t.Setenv("SIMPLEENV_TEST_CHAR_MARK", "✓")
+		t.Setenv("SIMPLEENV_TEST_CHAR_COUNT", "7")
+
+		var c cfg
+		if err := Load(&c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		mark := '✓'
+		want := cfg{Delim: '|', Quote: '"', Mark: &mark, Count: 7}
+		if !reflect.DeepEqual(c, want) {
+			t.Fatalf("unexpected config:\ngot:  %+v\nwant: %+v", c, want)
+		}
+
+		if err := Validate(&c); err != nil {
+			t.Fatalf("expected loaded char config to validate, got %v", err)
+		}
+
+		out, err := Marshal(&c)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !strings.Contains(out, "SIMPLEENV_TEST_CHAR_DELIM=|\n") {
+			t.Fatalf("expected char field to marshal as a character, got:\n%s", out)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		key      string
+		value    string
+		contains string
+	}{
+		{name: "rune rejects several characters", key: "SIMPLEENV_TEST_CHAR_MARK", value: "ab", contains: "expected a single character"},
+		{name: "rune rejects empty value", key: "SIMPLEENV_TEST_CHAR_DELIM", value: "", contains: "a non-empty value"},
+		{name: "byte rejects non-ASCII", key: "SIMPLEENV_TEST_CHAR_QUOTE", value: "é", contains: "expected a single ASCII character"},
+		{name: "constraints see the character", key: "SIMPLEENV_TEST_CHAR_DELIM", value: "/", contains: "expected one of"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("SIMPLEENV_TEST_CHAR_DELIM", "|")
+			t.Setenv("SIMPLEENV_TEST_CHAR_QUOTE", "'")
+			t.Setenv("SIMPLEENV_TEST_CHAR_COUNT", "1")
+			unsetEnv(t, "SIMPLEENV_TEST_CHAR_MARK")
+			t.Setenv(tc.key, tc.value)
+
+			err := Load(&cfg{})
+			if err == nil || !strings.Contains(err.Error(), tc.contains) {
+				t.Fatalf("expected error containing %q, got %v", tc.contains, err)
+			}
+		})
+	}
+
+	t.Run("char requires a rune or byte field", func(t *testing.T) {
+		type invalid struct {
+			Delim string `env:"SIMPLEENV_TEST_CHAR_INVALID;char"`
+		}
+
+		err := Load(&invalid{})
+		if !errors.Is(err, ErrInvalidTag) {
+			t.Fatalf("expected errors.Is(err, ErrInvalidTag), got %v", err)
+		}
+	})
+
+	t.Run("char cannot be combined with numeric constraints", func(t *testing.T) {
+		type invalid struct {
+			Delim rune `env:"SIMPLEENV_TEST_CHAR_INVALID;char;min=32"`
+		}
+
+		err := Load(&invalid{})
+		if !errors.Is(err, ErrInvalidTag) {
+			t.Fatalf("expected errors.Is(err, ErrInvalidTag), got %v", err)
+		}
+	})
+}
+
+func TestLoadByteSlices(t *testing.T) {
+	type cfg struct {
+		Key   []byte  `env:"SIMPLEENV_TEST_BYTES_KEY;format=base64"`
+		Salt  *[]byte `env:"SIMPLEENV_TEST_BYTES_SALT;format=BASE64;optional"`
+		Token []byte  `env:"SIMPLEENV_TEST_BYTES_TOKEN"`
+	}
+
+	t.Run("base64 values are decoded", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_BYTES_KEY", "AAEC/w==")
+		t.Setenv("SIMPLEENV_TEST_BYTES_SALT", "c2FsdA==")
+		t.Setenv("SIMPLEENV_TEST_BYTES_TOKEN", "raw-token")
+
+		var c cfg
+		if err := Load(&c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		salt := []byte("salt")
+		want := cfg{Key: []byte{0x00, 0x01, 0x02, 0xff}, Salt: &salt, Token: []byte("raw-token")}
+		if !reflect.DeepEqual(c, want) {
+			t.Fatalf("unexpected config:\ngot:  %+v\nwant: %+v", c, want)
+		}
+
+		if err := Validate(&c); err != nil {
+			t.Fatalf("expected loaded bytes config to validate, got %v", err)
+		}
+
+		out, err := Marshal(&c)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !strings.Contains(out, "SIMPLEENV_TEST_BYTES_KEY=AAEC/w==\n") {
+			t.Fatalf("expected base64 field to marshal encoded, got:\n%s", out)
+		}
+	})
+
+	t.Run("invalid base64 names the field", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_BYTES_KEY", "not base64!")
+		t.Setenv("SIMPLEENV_TEST_BYTES_TOKEN", "raw-token")
+
+		var c cfg
+		err := Load(&c)
+		if !errors.Is(err, ErrConstraint) {
+			t.Fatalf("expected errors.Is(err, ErrConstraint), got %v", err)
+		}
+		for _, want := range []string{`field "Key"`, "expected valid base64"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Fatalf("expected error to contain %q, got %q", want, err.Error())
+			}
+		}
+	})
+}
+
+func TestLoadNegativeNumbers(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType reflect.Type
+		tag       string
+		envValue  string
+		wantValue any
+		wantErr   string
+	}{
+		{name: "int within negative range", fieldType: reflect.TypeOf(0), tag: "SIMPLEENV_TEST_NEG_OFFSET;min=-10;max=10", envValue: "-3", wantValue: -3},
+		{name: "int at negative min", fieldType: reflect.TypeOf(0), tag: "SIMPLEENV_TEST_NEG_OFFSET;min=-10;max=10", envValue: "-10", wantValue: -10},
+		{name: "int below negative min", fieldType: reflect.TypeOf(0), tag: "SIMPLEENV_TEST_NEG_OFFSET;min=-10;max=10", envValue: "-11", wantErr: "expected a value >= -10"},
+		{name: "int above negative max", fieldType: reflect.TypeOf(0), tag: "SIMPLEENV_TEST_NEG_OFFSET;min=-10;max=-5", envValue: "-4", wantErr: "expected a value <= -5"},
+		{name: "int negative gt", fieldType: reflect.TypeOf(0), tag: "SIMPLEENV_TEST_NEG_OFFSET;gt=-1", envValue: "-1", wantErr: "expected a value > -1"},
+		{name: "int negative hex literal", fieldType: reflect.TypeOf(0), tag: "SIMPLEENV_TEST_NEG_OFFSET;min=-16", envValue: "-0x10", wantValue: -16},
+		{name: "int8 minimum", fieldType: reflect.TypeOf(int8(0)), tag: "SIMPLEENV_TEST_NEG_INT8;min=-128", envValue: "-128", wantValue: int8(-128)},
+		{name: "int64 negative bound beyond float precision", fieldType: reflect.TypeOf(int64(0)), tag: "SIMPLEENV_TEST_NEG_INT64;max=-9007199254740993", envValue: "-9007199254740992", wantErr: "expected a value <= -9007199254740993"},
+		{name: "int negative multipleof", fieldType: reflect.TypeOf(0), tag: "SIMPLEENV_TEST_NEG_OFFSET;multipleof=5", envValue: "-15", wantValue: -15},
+		{name: "uint rejects negative value", fieldType: reflect.TypeOf(uint(0)), tag: "SIMPLEENV_TEST_NEG_UINT;min=-1", envValue: "-1", wantErr: "non-negative integer"},
+		{name: "float within negative range", fieldType: reflect.TypeOf(0.0), tag: "SIMPLEENV_TEST_NEG_FLOAT;min=-1.5;max=-0.5", envValue: "-1.25", wantValue: -1.25},
+		{name: "float below negative min", fieldType: reflect.TypeOf(0.0), tag: "SIMPLEENV_TEST_NEG_FLOAT;min=-1.5", envValue: "-1.75", wantErr: "expected a value >= -1.5"},
+		{name: "duration negative bound", fieldType: reflect.TypeOf(time.Duration(0)), tag: "SIMPLEENV_TEST_NEG_DURATION;min=-1m", envValue: "-30s", wantValue: -30 * time.Second},
+		{name: "negative default", fieldType: reflect.TypeOf(0), tag: "SIMPLEENV_TEST_NEG_UNSET;default=-7;min=-10", wantValue: -7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var envValue *string
+			if tt.envValue != "" {
+				envValue = strPtr(tt.envValue)
+			}
+
+			value, err := loadSingleField(t, tt.fieldType, tt.tag, envValue)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(value.Interface(), tt.wantValue) {
+				t.Fatalf("unexpected value: got %#v, want %#v", value.Interface(), tt.wantValue)
+			}
+		})
+	}
+
+	t.Run("negative values round-trip through Validate and Marshal", func(t *testing.T) {
+		type cfg struct {
+			Offset int     `env:"SIMPLEENV_TEST_NEG_OFFSET;min=-10;max=10"`
+			Skew   float64 `env:"SIMPLEENV_TEST_NEG_FLOAT;min=-1.5"`
+		}
+
+		c := cfg{Offset: -3, Skew: -1.25}
+		if err := Validate(&c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := Validate(&cfg{Offset: -11, Skew: -1.25}); !errors.Is(err, ErrConstraint) {
+			t.Fatalf("expected constraint error, got %v", err)
+		}
+
+		out, err := Marshal(&c)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		values, err := parseDotenv(out, false)
+		if err != nil {
+			t.Fatalf("expected marshaled output to parse, got %v", err)
+		}
+
+		var loaded cfg
+		if err := LoadFrom(&loaded, values); err != nil {
+			t.Fatalf("expected marshaled config to load, got %v", err)
+		}
+		if loaded != c {
+			t.Fatalf("expected round-trip to keep %+v, got %+v", c, loaded)
+		}
+	})
+}
+
+func TestLoadSizeValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType reflect.Type
+		tag       string
+		envValue  string
+		wantValue any
+		wantErr   string
+	}{
+		{name: "SI megabytes", fieldType: reflect.TypeOf(int64(0)), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "10MB", wantValue: int64(10_000_000)},
+		{name: "IEC gibibytes", fieldType: reflect.TypeOf(int64(0)), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "2GiB", wantValue: int64(2 << 30)},
+		{name: "lowercase suffix", fieldType: reflect.TypeOf(int(0)), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "512kb", wantValue: 512_000},
+		{name: "space before suffix", fieldType: reflect.TypeOf(uint64(0)), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "4 KiB", wantValue: uint64(4096)},
+		{name: "plain bytes", fieldType: reflect.TypeOf(int(0)), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "4096", wantValue: 4096},
+		{name: "fractional size", fieldType: reflect.TypeOf(int64(0)), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "1.5KiB", wantValue: int64(1536)},
+		{name: "size pointer", fieldType: reflect.TypeOf((*int64)(nil)), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "1B", wantValue: func() *int64 { n := int64(1); return &n }()},
+		{name: "size bounds use suffixes", fieldType: reflect.TypeOf(int64(0)), tag: "SIMPLEENV_TEST_SIZE;size;min=1KB;max=1MiB", envValue: "1MB", wantValue: int64(1_000_000)},
+		{name: "size above max", fieldType: reflect.TypeOf(int64(0)), tag: "SIMPLEENV_TEST_SIZE;size;max=1MiB", envValue: "2MB", wantErr: "expected a value <= 1MiB"},
+		{name: "size multipleof", fieldType: reflect.TypeOf(int64(0)), tag: "SIMPLEENV_TEST_SIZE;size;multipleof=4096", envValue: "1MB", wantErr: "a multiple of 4096"},
+		{name: "unknown suffix", fieldType: reflect.TypeOf(int64(0)), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "10MX", wantErr: `field "Value" from ENV["SIMPLEENV_TEST_SIZE"]: got "10MX", expected a size in bytes`},
+		{name: "ambiguous single-letter suffix", fieldType: reflect.TypeOf(int64(0)), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "10M", wantErr: "expected a size in bytes"},
+		{name: "negative size", fieldType: reflect.TypeOf(int64(0)), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "-1KB", wantErr: "expected a size in bytes"},
+		{name: "fractional byte", fieldType: reflect.TypeOf(int64(0)), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "1.5B", wantErr: "expected a size in bytes"},
+		{name: "overflow", fieldType: reflect.TypeOf(int32(0)), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "4GiB", wantErr: "expected a size that fits in int32"},
+		{name: "invalid size bound", fieldType: reflect.TypeOf(int64(0)), tag: "SIMPLEENV_TEST_SIZE;size;max=lots", envValue: "1KB", wantErr: `"max=lots" must be a valid size`},
+		{name: "size requires an integer field", fieldType: reflect.TypeOf(""), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "1KB", wantErr: "size is only supported for integer types"},
+		{name: "size rejects duration fields", fieldType: reflect.TypeOf(time.Duration(0)), tag: "SIMPLEENV_TEST_SIZE;size", envValue: "1KB", wantErr: "size is only supported for integer types"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := loadSingleField(t, tt.fieldType, tt.tag, strPtr(tt.envValue))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(value.Interface(), tt.wantValue) {
+				t.Fatalf("unexpected value: got %#v, want %#v", value.Interface(), tt.wantValue)
+			}
+		})
+	}
+
+	t.Run("size fields validate and marshal as bytes", func(t *testing.T) {
+		type cfg struct {
+			MaxUpload int64 `env:"SIMPLEENV_TEST_SIZE_UPLOAD;size;max=1GiB"`
+		}
+
+		if err := Validate(&cfg{MaxUpload: 1 << 30}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := Validate(&cfg{MaxUpload: 1<<30 + 1}); !errors.Is(err, ErrConstraint) {
+			t.Fatalf("expected constraint error, got %v", err)
+		}
+
+		out, err := Marshal(&cfg{MaxUpload: 10_000_000})
+		if err != nil || out != "SIMPLEENV_TEST_SIZE_UPLOAD=10000000\n" {
+			t.Fatalf("unexpected marshal output %q (%v)", out, err)
+		}
+	})
+}
+
+func TestLoadIPAddresses(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType reflect.Type
+		tag       string
+		envValue  string
+		wantValue any
+		wantErr   string
+	}{
+		{name: "net.IP v4", fieldType: reflect.TypeOf(net.IP{}), tag: "SIMPLEENV_TEST_IP", envValue: "10.0.0.1", wantValue: net.ParseIP("10.0.0.1")},
+		{name: "net.IP v6", fieldType: reflect.TypeOf(net.IP{}), tag: "SIMPLEENV_TEST_IP;format=IPV6", envValue: "::1", wantValue: net.IPv6loopback},
+		{name: "netip.Addr", fieldType: reflect.TypeOf(netip.Addr{}), tag: "SIMPLEENV_TEST_IP", envValue: "192.168.1.10", wantValue: netip.MustParseAddr("192.168.1.10")},
+		{name: "netip.Addr pointer", fieldType: reflect.TypeOf((*netip.Addr)(nil)), tag: "SIMPLEENV_TEST_IP", envValue: "fe80::1", wantValue: netip.MustParseAddr("fe80::1")},
+		{name: "netip.Addr list", fieldType: reflect.TypeOf([]netip.Addr{}), tag: "SIMPLEENV_TEST_IP", envValue: "10.0.0.1, 10.0.0.2", wantValue: []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.2")}},
+		{name: "invalid net.IP", fieldType: reflect.TypeOf(net.IP{}), tag: "SIMPLEENV_TEST_IP", envValue: "10.0.0.256", wantErr: `field "Value" from ENV["SIMPLEENV_TEST_IP"]: got "10.0.0.256", expected a valid IP address`},
+		{name: "invalid netip.Addr", fieldType: reflect.TypeOf(netip.Addr{}), tag: "SIMPLEENV_TEST_IP", envValue: "localhost", wantErr: `got "localhost", expected a valid IP address (for example: 10.0.0.1 or ::1): ParseAddr("localhost")`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := loadSingleField(t, tt.fieldType, tt.tag, strPtr(tt.envValue))
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrParse) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected parse error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if value.Kind() == reflect.Pointer {
+				value = value.Elem()
+			}
+			if !reflect.DeepEqual(value.Interface(), tt.wantValue) {
+				t.Fatalf("unexpected value: got %#v, want %#v", value.Interface(), tt.wantValue)
+			}
+		})
+	}
+
+	t.Run("secret addresses stay masked", func(t *testing.T) {
+		for _, fieldType := range []reflect.Type{reflect.TypeOf(net.IP{}), reflect.TypeOf(netip.Addr{})} {
+			_, err := loadSingleField(t, fieldType, "SIMPLEENV_TEST_IP;secret", strPtr("10.0.0.999"))
+			if !errors.Is(err, ErrParse) || strings.Contains(err.Error(), "10.0.0.999") || !strings.Contains(err.Error(), `got "****"`) {
+				t.Fatalf("%s: expected masked parse error, got %v", fieldType, err)
+			}
+		}
+	})
+
+	t.Run("marshal round trip", func(t *testing.T) {
+		type cfg struct {
+			Bind net.IP     `env:"SIMPLEENV_TEST_IP_BIND"`
+			Peer netip.Addr `env:"SIMPLEENV_TEST_IP_PEER"`
+		}
+
+		out, err := Marshal(&cfg{Bind: net.ParseIP("0.0.0.0"), Peer: netip.MustParseAddr("::1")})
+		if err != nil || out != "SIMPLEENV_TEST_IP_BIND=0.0.0.0\nSIMPLEENV_TEST_IP_PEER=::1\n" {
+			t.Fatalf("unexpected marshal output %q (%v)", out, err)
+		}
+
+		var c cfg
+		if err := Unmarshal([]byte(out), &c); err != nil || !c.Bind.Equal(net.IPv4zero) || c.Peer != netip.IPv6Loopback() {
+			t.Fatalf("unexpected round trip: %+v (%v)", c, err)
+		}
+	})
+}
+
+func TestLoadURLs(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType reflect.Type
+		tag       string
+		envValue  string
+		wantURL   string
+		wantKind  error
+		wantErr   string
+	}{
+		{name: "url pointer", fieldType: reflect.TypeOf((*url.URL)(nil)), tag: "SIMPLEENV_TEST_URL", envValue: "https://api.example.com/v1?region=eu", wantURL: "https://api.example.com/v1?region=eu"},
+		{name: "url value", fieldType: reflect.TypeOf(url.URL{}), tag: "SIMPLEENV_TEST_URL", envValue: "postgres://app:secret@db:5432/app", wantURL: "postgres://app:secret@db:5432/app"},
+		{name: "allowed scheme", fieldType: reflect.TypeOf((*url.URL)(nil)), tag: "SIMPLEENV_TEST_URL;schemes=postgres,postgresql", envValue: "postgresql://db/app", wantURL: "postgresql://db/app"},
+		{name: "invalid url", fieldType: reflect.TypeOf((*url.URL)(nil)), tag: "SIMPLEENV_TEST_URL", envValue: "http://[::1", wantKind: ErrParse, wantErr: `field "Value" from ENV["SIMPLEENV_TEST_URL"]: got "http://[::1", expected a valid URL: parse`},
+		{name: "scheme-relative url", fieldType: reflect.TypeOf((*url.URL)(nil)), tag: "SIMPLEENV_TEST_URL", envValue: "//cdn.example.com/assets", wantURL: "//cdn.example.com/assets"},
+		{name: "plain text is not a url", fieldType: reflect.TypeOf((*url.URL)(nil)), tag: "SIMPLEENV_TEST_URL", envValue: "not a url at all", wantKind: ErrParse, wantErr: `got "not a url at all", expected a valid URL with a scheme or host`},
+		{name: "malformed secret url stays masked", fieldType: reflect.TypeOf((*url.URL)(nil)), tag: "SIMPLEENV_TEST_URL;secret", envValue: "postgres://user:hunter2@ho st/db", wantKind: ErrParse, wantErr: `got "****", expected a valid URL`},
+		{name: "disallowed scheme", fieldType: reflect.TypeOf((*url.URL)(nil)), tag: "SIMPLEENV_TEST_URL;schemes=postgres,postgresql", envValue: "mysql://db/app", wantKind: ErrConstraint, wantErr: "expected a valid URL with postgres/postgresql scheme"},
+		{name: "schemes require a host", fieldType: reflect.TypeOf((*url.URL)(nil)), tag: "SIMPLEENV_TEST_URL;schemes=https", envValue: "/relative/path", wantKind: ErrConstraint, wantErr: "expected a valid URL with https scheme"},
+		{name: "format=URL checks default schemes", fieldType: reflect.TypeOf((*url.URL)(nil)), tag: "SIMPLEENV_TEST_URL;format=URL", envValue: "ftp://files.local", wantKind: ErrConstraint, wantErr: "expected a valid URL with http/https scheme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := loadSingleField(t, tt.fieldType, tt.tag, strPtr(tt.envValue))
+			if tt.wantErr != "" {
+				if !errors.Is(err, tt.wantKind) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected %v containing %q, got %v", tt.wantKind, tt.wantErr, err)
+				}
+				if strings.Contains(tt.tag, ";secret") && strings.Contains(err.Error(), "hunter2") {
+					t.Fatalf("expected secret URL to stay masked, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if value.Kind() == reflect.Pointer {
+				value = value.Elem()
+			}
+			if got := value.Addr().Interface().(*url.URL).String(); got != tt.wantURL {
+				t.Fatalf("unexpected URL: got %q, want %q", got, tt.wantURL)
+			}
+		})
+	}
+
+	t.Run("validate and marshal", func(t *testing.T) {
+		type cfg struct {
+			API *url.URL `env:"SIMPLEENV_TEST_URL_API;schemes=https"`
+		}
+
+		c := cfg{API: &url.URL{Scheme: "https", Host: "api.local", Path: "/v1"}}
+		if err := Validate(&c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		out, err := Marshal(&c)
+		if err != nil || out != "SIMPLEENV_TEST_URL_API=https://api.local/v1\n" {
+			t.Fatalf("unexpected marshal output %q (%v)", out, err)
+		}
+
+		c.API.Scheme = "http"
+		if err := Validate(&c); !errors.Is(err, ErrConstraint) {
+			t.Fatalf("expected Validate to check schemes, got %v", err)
+		}
+	})
+}
+
+func TestLoadComplexValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType reflect.Type
+		tag       string
+		envValue  string
+		wantValue any
+		wantErr   string
+	}{
+		{name: "complex128", fieldType: reflect.TypeOf(complex128(0)), tag: "SIMPLEENV_TEST_COMPLEX", envValue: "1+2i", wantValue: complex(1, 2)},
+		{name: "parenthesized", fieldType: reflect.TypeOf(complex128(0)), tag: "SIMPLEENV_TEST_COMPLEX", envValue: "(-0.5-1e3i)", wantValue: complex(-0.5, -1e3)},
+		{name: "real only", fieldType: reflect.TypeOf(complex64(0)), tag: "SIMPLEENV_TEST_COMPLEX", envValue: "3", wantValue: complex64(3)},
+		{name: "pointer", fieldType: reflect.TypeOf((*complex128)(nil)), tag: "SIMPLEENV_TEST_COMPLEX", envValue: "2i", wantValue: complex(0, 2)},
+		{name: "invalid value", fieldType: reflect.TypeOf(complex128(0)), tag: "SIMPLEENV_TEST_COMPLEX", envValue: "1+2j", wantErr: `field "Value" from ENV["SIMPLEENV_TEST_COMPLEX"]: got "1+2j", expected a valid complex128 (for example: 1+2i)`},
+		{name: "bounds are rejected", fieldType: reflect.TypeOf(complex128(0)), tag: "SIMPLEENV_TEST_COMPLEX;min=1", envValue: "1", wantErr: "min is not supported for complex types"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := loadSingleField(t, tt.fieldType, tt.tag, strPtr(tt.envValue))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if value.Kind() == reflect.Pointer {
+				value = value.Elem()
+			}
+			if value.Interface() != tt.wantValue {
+				t.Fatalf("unexpected value: got %v, want %v", value.Interface(), tt.wantValue)
+			}
+		})
+	}
+
+	t.Run("marshal round trip", func(t *testing.T) {
+		type cfg struct {
+			Gain complex128 `env:"SIMPLEENV_TEST_COMPLEX_GAIN"`
+		}
+
+		out, err := Marshal(&cfg{Gain: complex(1.5, -2)})
+		if err != nil || out != "SIMPLEENV_TEST_COMPLEX_GAIN=1.5-2i\n" {
+			t.Fatalf("unexpected marshal output %q (%v)", out, err)
+		}
+
+		var c cfg
+		if err := Unmarshal([]byte(out), &c); err != nil || c.Gain != complex(1.5, -2) {
+			t.Fatalf("unexpected round trip: %v (%v)", c.Gain, err)
+		}
+	})
+}
+
+func TestLoadPercentValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType reflect.Type
+		tag       string
+		envValue  string
+		wantValue any
+		wantErr   string
+	}{
+		{name: "percent suffix", fieldType: reflect.TypeOf(float64(0)), tag: "SIMPLEENV_TEST_PERCENT;percent", envValue: "75%", wantValue: 0.75},
+		{name: "plain fraction", fieldType: reflect.TypeOf(float64(0)), tag: "SIMPLEENV_TEST_PERCENT;percent", envValue: "0.75", wantValue: 0.75},
+		{name: "decimal percent", fieldType: reflect.TypeOf(float64(0)), tag: "SIMPLEENV_TEST_PERCENT;percent", envValue: "33.3 %", wantValue: 0.333},
+		{name: "float32 field", fieldType: reflect.TypeOf(float32(0)), tag: "SIMPLEENV_TEST_PERCENT;percent", envValue: "12.5%", wantValue: float32(0.125)},
+		{name: "full range", fieldType: reflect.TypeOf(float64(0)), tag: "SIMPLEENV_TEST_PERCENT;percent", envValue: "100%", wantValue: 1.0},
+		{name: "above 100 percent", fieldType: reflect.TypeOf(float64(0)), tag: "SIMPLEENV_TEST_PERCENT;percent", envValue: "150%", wantErr: `field "Value" from ENV["SIMPLEENV_TEST_PERCENT"]: got "150%", expected a percentage between 0% and 100%`},
+		{name: "negative fraction", fieldType: reflect.TypeOf(float64(0)), tag: "SIMPLEENV_TEST_PERCENT;percent", envValue: "-0.1", wantErr: "a percentage between 0% and 100%"},
+		{name: "bounds replace default range", fieldType: reflect.TypeOf(float64(0)), tag: "SIMPLEENV_TEST_PERCENT;percent;max=200%", envValue: "150%", wantValue: 1.5},
+		{name: "bounds accept fractions", fieldType: reflect.TypeOf(float64(0)), tag: "SIMPLEENV_TEST_PERCENT;percent;min=0.1;max=50%", envValue: "60%", wantErr: "expected a value <= 50%"},
+		{name: "invalid percent", fieldType: reflect.TypeOf(float64(0)), tag: "SIMPLEENV_TEST_PERCENT;percent", envValue: "lots%", wantErr: "expected a percentage such as 75% or a fraction such as 0.75"},
+		{name: "NaN is rejected", fieldType: reflect.TypeOf(float64(0)), tag: "SIMPLEENV_TEST_PERCENT;percent", envValue: "NaN", wantErr: "expected a percentage"},
+		{name: "invalid percent bound", fieldType: reflect.TypeOf(float64(0)), tag: "SIMPLEENV_TEST_PERCENT;percent;max=most", envValue: "50%", wantErr: `"max=most" must be a valid percentage`},
+		{name: "percent requires a float field", fieldType: reflect.TypeOf(int(0)), tag: "SIMPLEENV_TEST_PERCENT;percent", envValue: "50%", wantErr: "percent is only supported for float types"},
+		{name: "percent rejects json", fieldType: reflect.TypeOf(float64(0)), tag: "SIMPLEENV_TEST_PERCENT;percent;json", envValue: "0.5", wantErr: "percent cannot be used together with json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := loadSingleField(t, tt.fieldType, tt.tag, strPtr(tt.envValue))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(value.Interface(), tt.wantValue) {
+				t.Fatalf("unexpected value: got %#v, want %#v", value.Interface(), tt.wantValue)
+			}
+		})
+	}
+
+	t.Run("percent fields validate as fractions", func(t *testing.T) {
+		type cfg struct {
+			SampleRate float64 `env:"SIMPLEENV_TEST_PERCENT_SAMPLE;percent"`
+		}
+
+		if err := Validate(&cfg{SampleRate: 0.25}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := Validate(&cfg{SampleRate: 2}); !errors.Is(err, ErrConstraint) {
+			t.Fatalf("expected constraint error, got %v", err)
+		}
+	})
+}
+
+func TestLoadArrays(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType reflect.Type
+		tag       string
+		envValue  string
+		wantValue any
+		wantErr   string
+	}{
+		{name: "float triple", fieldType: reflect.TypeOf([3]float64{}), tag: "SIMPLEENV_TEST_ARRAY", envValue: "1.5, -2,3e2", wantValue: [3]float64{1.5, -2, 300}},
+		{name: "rgb bytes", fieldType: reflect.TypeOf([3]uint8{}), tag: "SIMPLEENV_TEST_ARRAY", envValue: "255,128,0", wantValue: [3]uint8{255, 128, 0}},
+		{name: "strings with custom separator", fieldType: reflect.TypeOf([2]string{}), tag: "SIMPLEENV_TEST_ARRAY;sep=|", envValue: "a,b | c", wantValue: [2]string{"a,b", "c"}},
+		{name: "durations", fieldType: reflect.TypeOf([2]time.Duration{}), tag: "SIMPLEENV_TEST_ARRAY", envValue: "1s,2m", wantValue: [2]time.Duration{time.Second, 2 * time.Minute}},
+		{name: "array pointer", fieldType: reflect.TypeOf((*[2]int)(nil)), tag: "SIMPLEENV_TEST_ARRAY", envValue: "1,2", wantValue: &[2]int{1, 2}},
+		{name: "too few values", fieldType: reflect.TypeOf([3]float64{}), tag: "SIMPLEENV_TEST_ARRAY", envValue: "1,2", wantErr: `field "Value" from ENV["SIMPLEENV_TEST_ARRAY"]: got "1,2", expected exactly 3 values separated by ",", got 2`},
+		{name: "too many values", fieldType: reflect.TypeOf([2]int{}), tag: "SIMPLEENV_TEST_ARRAY", envValue: "1,2,3", wantErr: `expected exactly 2 values separated by ",", got 3`},
+		{name: "invalid element", fieldType: reflect.TypeOf([3]float64{}), tag: "SIMPLEENV_TEST_ARRAY", envValue: "1,x,3", wantErr: `got "1,x,3", expected element 1 to be a valid float64`},
+		{name: "element overflow", fieldType: reflect.TypeOf([3]uint8{}), tag: "SIMPLEENV_TEST_ARRAY", envValue: "255,256,0", wantErr: "expected element 1 to be"},
+		{name: "nested arrays are unsupported", fieldType: reflect.TypeOf([2][2]int{}), tag: "SIMPLEENV_TEST_ARRAY", envValue: "1,2", wantErr: "unsupported type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := loadSingleField(t, tt.fieldType, tt.tag, strPtr(tt.envValue))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(value.Interface(), tt.wantValue) {
+				t.Fatalf("unexpected value: got %#v, want %#v", value.Interface(), tt.wantValue)
+			}
+		})
+	}
+
+	t.Run("arrays validate and marshal with their separator", func(t *testing.T) {
+		type cfg struct {
+			Coords [3]float64 `env:"SIMPLEENV_TEST_ARRAY_COORDS;sep=|"`
+		}
+
+		want := cfg{Coords: [3]float64{1, 2.5, 3}}
+		if err := Validate(&want); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		out, err := Marshal(&want)
+		if err != nil || out != "SIMPLEENV_TEST_ARRAY_COORDS=1|2.5|3\n" {
+			t.Fatalf("unexpected marshal output %q (%v)", out, err)
+		}
+
+		var got cfg
+		if err := LoadFrom(&got, map[string]string{"SIMPLEENV_TEST_ARRAY_COORDS": "1|2.5|3"}); err != nil || got != want {
+			t.Fatalf("expected round trip to %+v, got %+v (%v)", want, got, err)
+		}
+	})
+}
+
+func TestLoadTypedSlices(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType reflect.Type
+		tag       string
+		envValue  string
+		wantValue any
+		wantErr   string
+	}{
+		{name: "durations", fieldType: reflect.TypeOf([]time.Duration{}), tag: "SIMPLEENV_TEST_SLICE", envValue: "1s, 2s,4s", wantValue: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}},
+		{name: "times with layout", fieldType: reflect.TypeOf([]time.Time{}), tag: "SIMPLEENV_TEST_SLICE;layout=2006-01-02", envValue: "2024-01-01,2024-06-30", wantValue: []time.Time{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)}},
+		{name: "times default to RFC3339", fieldType: reflect.TypeOf([]time.Time{}), tag: "SIMPLEENV_TEST_SLICE;sep=|", envValue: "2024-01-01T10:00:00Z", wantValue: []time.Time{time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)}},
+		{name: "ints", fieldType: reflect.TypeOf([]int{}), tag: "SIMPLEENV_TEST_SLICE", envValue: "80,443,0x1F90", wantValue: []int{80, 443, 8080}},
+		{name: "allowempty gives an empty slice", fieldType: reflect.TypeOf([]time.Duration{}), tag: "SIMPLEENV_TEST_SLICE;allowempty", envValue: "", wantValue: []time.Duration(nil)},
+		{name: "invalid duration reports the index", fieldType: reflect.TypeOf([]time.Duration{}), tag: "SIMPLEENV_TEST_SLICE", envValue: "1s,2x,4s", wantErr: `got "1s,2x,4s", expected element 1 to be a valid time.Duration`},
+		{name: "invalid time reports the index", fieldType: reflect.TypeOf([]time.Time{}), tag: "SIMPLEENV_TEST_SLICE;layout=2006-01-02", envValue: "2024-01-01,2024-13-01", wantErr: `expected element 1 to be a valid time in layout "2006-01-02"`},
+		{name: "empty element", fieldType: reflect.TypeOf([]int{}), tag: "SIMPLEENV_TEST_SLICE", envValue: "1,,3", wantErr: "expected element 1 to be"},
+		{name: "IP addresses", fieldType: reflect.TypeOf([]net.IP{}), tag: "SIMPLEENV_TEST_SLICE", envValue: "10.0.0.1, ::1", wantValue: []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("::1")}},
+		{name: "invalid IP address reports the index", fieldType: reflect.TypeOf([]net.IP{}), tag: "SIMPLEENV_TEST_SLICE", envValue: "10.0.0.1,10.0.0.999", wantErr: "expected element 1 to be a valid IP address"},
+		{name: "URL pointers", fieldType: reflect.TypeOf([]*url.URL{}), tag: "SIMPLEENV_TEST_SLICE", envValue: "https://a.example.com,https://b.example.com/v1", wantValue: []*url.URL{{Scheme: "https", Host: "a.example.com"}, {Scheme: "https", Host: "b.example.com", Path: "/v1"}}},
+		{name: "invalid URL reports the index", fieldType: reflect.TypeOf([]*url.URL{}), tag: "SIMPLEENV_TEST_SLICE", envValue: "https://a.example.com,not a url", wantErr: "expected element 1 to be a valid URL"},
+		{name: "nested slices are unsupported", fieldType: reflect.TypeOf([][]int{}), tag: "SIMPLEENV_TEST_SLICE", envValue: "1", wantErr: "unsupported type"},
+		{name: "numeric bounds are rejected on slices", fieldType: reflect.TypeOf([]int{}), tag: "SIMPLEENV_TEST_SLICE;min=1", envValue: "1,2", wantErr: "min is not supported for slice and array types"},
+		{name: "numeric bounds are rejected on arrays", fieldType: reflect.TypeOf([2]time.Duration{}), tag: "SIMPLEENV_TEST_SLICE;lte=1s", envValue: "1s,2s", wantErr: "lte is not supported for slice and array types"},
+		{name: "multipleof is rejected on slices", fieldType: reflect.TypeOf([]int{}), tag: "SIMPLEENV_TEST_SLICE;multipleof=2", envValue: "2,4", wantErr: "multipleof is only supported for integer types"},
+		{name: "other pointer elements are unsupported", fieldType: reflect.TypeOf([]*int{}), tag: "SIMPLEENV_TEST_SLICE", envValue: "1", wantErr: "unsupported type"},
+		{name: "layout requires time elements", fieldType: reflect.TypeOf([]time.Duration{}), tag: "SIMPLEENV_TEST_SLICE;layout=2006", envValue: "1s", wantErr: "layout is only supported for time.Time types and lists of them"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := loadSingleField(t, tt.fieldType, tt.tag, strPtr(tt.envValue))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
 			if !reflect.DeepEqual(value.Interface(), tt.wantValue) {
 				t.Fatalf("unexpected value: got %#v, want %#v", value.Interface(), tt.wantValue)
 			}
 		})
 	}
+
+	t.Run("typed slices validate and marshal", func(t *testing.T) {
+		type cfg struct {
+			Backoffs []time.Duration `env:"SIMPLEENV_TEST_SLICE_BACKOFFS"`
+		}
+
+		c := cfg{Backoffs: []time.Duration{time.Second, 90 * time.Second}}
+		if err := Validate(&c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		out, err := Marshal(&c)
+		if err != nil || out != "SIMPLEENV_TEST_SLICE_BACKOFFS=1s,1m30s\n" {
+			t.Fatalf("unexpected marshal output %q (%v)", out, err)
+		}
+	})
+	t.Run("numeric bounds on lists are tag errors even when unset", func(t *testing.T) {
+		var c struct {
+			Ports []int `env:"SIMPLEENV_TEST_SLICE_PORTS;optional;min=1"`
+		}
+		if err := LoadFrom(&c, nil); !errors.Is(err, ErrInvalidTag) {
+			t.Fatalf("expected errors.Is(err, ErrInvalidTag), got %v", err)
+		}
+	})
+
+	t.Run("IP and URL lists round trip", func(t *testing.T) {
+		type cfg struct {
+			Peers     []net.IP   `env:"SIMPLEENV_TEST_SLICE_PEERS"`
+			Upstreams []*url.URL `env:"SIMPLEENV_TEST_SLICE_UPSTREAMS"`
+		}
+
+		c := cfg{
+			Peers:     []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("::1")},
+			Upstreams: []*url.URL{{Scheme: "https", Host: "a.example.com"}},
+		}
+		if err := Validate(&c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		out, err := Marshal(&c)
+		if err != nil || out != "SIMPLEENV_TEST_SLICE_PEERS=10.0.0.1,::1\nSIMPLEENV_TEST_SLICE_UPSTREAMS=https://a.example.com\n" {
+			t.Fatalf("unexpected marshal output %q (%v)", out, err)
+		}
+
+		var got cfg
+		if err := Unmarshal([]byte(out), &got); err != nil || !reflect.DeepEqual(got, c) {
+			t.Fatalf("expected round trip to %+v, got %+v (%v)", c, got, err)
+		}
+
+		c.Upstreams = append(c.Upstreams, nil)
+		if _, err := Marshal(&c); err == nil || !strings.Contains(err.Error(), "element 1 is nil") {
+			t.Fatalf("expected nil element error, got %v", err)
+		}
+	})
+}
+
+func TestLoadOneOfFromEnv(t *testing.T) {
+	type cfg struct {
+		Environment string `env:"ENVIRONMENT;oneof=$ALLOWED_ENVS"`
+	}
+
+	tests := []struct {
+		name    string
+		source  MapSource
+		want    string
+		wantErr string
+		wantIs  error
+	}{
+		{name: "value in the referenced list", source: MapSource{"ENVIRONMENT": "prod", "ALLOWED_ENVS": "dev, prod"}, want: "prod"},
+		{name: "value outside the referenced list", source: MapSource{"ENVIRONMENT": "staging", "ALLOWED_ENVS": "dev,prod"}, wantErr: `got "staging", expected one of [dev,prod]`, wantIs: ErrConstraint},
+		{name: "escaped commas in the referenced list", source: MapSource{"ENVIRONMENT": "a,b", "ALLOWED_ENVS": `a\,b,c`}, want: "a,b"},
+		{name: "missing referenced variable", source: MapSource{"ENVIRONMENT": "prod"}, wantErr: `field "Environment" (ENV["ENVIRONMENT"]) reads its oneof values from ENV["ALLOWED_ENVS"], which is not set`, wantIs: ErrConstraint},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c cfg
+			err := LoadWithOptions(&c, WithSource(tt.source))
+			if tt.wantErr != "" {
+				if !errors.Is(err, tt.wantIs) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected %v containing %q, got %v", tt.wantIs, tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || c.Environment != tt.want {
+				t.Fatalf("expected %q, got %q (%v)", tt.want, c.Environment, err)
+			}
+		})
+	}
+
+	t.Run("braced references, defaults, and ignorecase", func(t *testing.T) {
+		type bracedCfg struct {
+			Region string `env:"REGION;default=EU;ignorecase;oneof=${REGIONS}"`
+		}
+
+		var c bracedCfg
+		err := LoadWithOptions(&c, WithSource(MapSource{"REGIONS": "us,eu"}))
+		if err != nil || c.Region != "EU" {
+			t.Fatalf("expected default to match, got %q (%v)", c.Region, err)
+		}
+	})
+
+	t.Run("missing referenced variable is fatal for LoadOrDefault", func(t *testing.T) {
+		var c cfg
+		warnings, err := LoadOrDefault(&c, WithSource(MapSource{"ENVIRONMENT": "prod"}))
+		if !errors.Is(err, ErrConstraint) || errors.Is(err, ErrMissingRequired) {
+			t.Fatalf("expected constraint error, got %v", err)
+		}
+		if len(warnings) != 0 {
+			t.Fatalf("expected no warnings, got %v", warnings)
+		}
+	})
+
+	t.Run("referenced variable counts as known", func(t *testing.T) {
+		err := LoadWithOptions(&cfg{}, WithExpectedPrefix("ALLOWED_"), WithSource(MapSource{"ENVIRONMENT": "dev", "ALLOWED_ENVS": "dev"}))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Validate skips references", func(t *testing.T) {
+		if err := Validate(&cfg{Environment: "anything"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("invalid reference is a tag error", func(t *testing.T) {
+		type badCfg struct {
+			Environment string `env:"ENVIRONMENT;oneof=$"`
+		}
+
+		err := LoadFrom(&badCfg{}, map[string]string{"ENVIRONMENT": "dev"})
+		if !errors.Is(err, ErrInvalidTag) || !strings.Contains(err.Error(), "oneof=$ must name an env var") {
+			t.Fatalf("expected tag error, got %v", err)
+		}
+	})
 }
 
-func TestLoadTagRules(t *testing.T) {
-	t.Run("field without env tag is skipped", func(t *testing.T) {
-		type cfg struct {
-			Name         string `env:"SIMPLEENV_TEST_WITH_TAG"`
-			DefaultValue string
+func TestLoadTextUnmarshalerError(t *testing.T) {
+	_, err := loadSingleField(t, reflect.TypeOf(logLevel("")), "SIMPLEENV_TEST_LOG_LEVEL", strPtr("verbose"))
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !errors.Is(err, errUnknownLogLevel) {
+		t.Fatalf("expected error to wrap unmarshal error, got %v", err)
+	}
+	for _, contains := range []string{`field "Value"`, `ENV["SIMPLEENV_TEST_LOG_LEVEL"]`, `got "verbose"`, "unknown log level"} {
+		if !strings.Contains(err.Error(), contains) {
+			t.Fatalf("expected error to contain %q, got %q", contains, err.Error())
 		}
+	}
+}
 
-		t.Setenv("SIMPLEENV_TEST_WITH_TAG", "from-env")
+func TestLoadWithTagName(t *testing.T) {
+	type cfg struct {
+		Name  string `cfg:"SIMPLEENV_TEST_TAG_NAME"`
+		Other string `env:"SIMPLEENV_TEST_TAG_NAME_OTHER_LIB"`
+	}
+
+	t.Setenv("SIMPLEENV_TEST_TAG_NAME", "from-cfg")
+	unsetEnv(t, "SIMPLEENV_TEST_TAG_NAME_OTHER_LIB")
+
+	var c cfg
+	err := LoadWithOptions(&c, WithTagName("cfg"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if c.Name != "from-cfg" {
+		t.Fatalf("expected Name from cfg tag, got %q", c.Name)
+	}
+	if c.Other != "" {
+		t.Fatalf("expected env tag to be ignored, got %q", c.Other)
+	}
+}
 
+func TestLoadWithTagSeparator(t *testing.T) {
+	type cfg struct {
+		Port    int      `env:"SIMPLEENV_TEST_TAG_SEP_PORT,min=1,max=65535"`
+		Mode    string   `env:"SIMPLEENV_TEST_TAG_SEP_MODE,oneof=dev,test,prod,default=dev"`
+		Hosts   []string `env:"SIMPLEENV_TEST_TAG_SEP_HOSTS,sep=|,optional"`
+		Version string   `env:"SIMPLEENV_TEST_TAG_SEP_VERSION,regex=^v[0-9]{1,3}$,optional"`
+	}
+
+	t.Run("options split on the separator", func(t *testing.T) {
 		var c cfg
-		err := Load(&c)
+		err := LoadWithOptions(&c,
+			WithTagSeparator(','),
+			WithSource(MapSource{"SIMPLEENV_TEST_TAG_SEP_PORT": "8080", "SIMPLEENV_TEST_TAG_SEP_HOSTS": "a|b", "SIMPLEENV_TEST_TAG_SEP_VERSION": "v12"}),
+		)
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
-		if c.Name != "from-env" {
-			t.Fatalf("expected Name from env, got %q", c.Name)
+
+		want := cfg{Port: 8080, Mode: "dev", Hosts: []string{"a", "b"}, Version: "v12"}
+		if !reflect.DeepEqual(c, want) {
+			t.Fatalf("unexpected config:\ngot:  %+v\nwant: %+v", c, want)
 		}
-		if c.DefaultValue != "" {
-			t.Fatalf("expected untagged field zero value, got %q", c.DefaultValue)
+	})
+
+	tests := []struct {
+		name     string
+		values   MapSource
+		contains string
+	}{
+		{name: "list option keeps every value", values: MapSource{"SIMPLEENV_TEST_TAG_SEP_PORT": "1", "SIMPLEENV_TEST_TAG_SEP_MODE": "stage"}, contains: "expected one of [dev,test,prod]"},
+		{name: "regex keeps its commas", values: MapSource{"SIMPLEENV_TEST_TAG_SEP_PORT": "1", "SIMPLEENV_TEST_TAG_SEP_VERSION": "v1234"}, contains: `regex "^v[0-9]{1,3}$"`},
+		{name: "constraints still apply", values: MapSource{"SIMPLEENV_TEST_TAG_SEP_PORT": "0"}, contains: "expected a value >= 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := LoadWithOptions(&cfg{}, WithTagSeparator(','), WithSource(tt.values))
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Fatalf("expected error containing %q, got %v", tt.contains, err)
+			}
+		})
+	}
+
+	t.Run("list values may match modifier names", func(t *testing.T) {
+		type formatCfg struct {
+			Format string `env:"LOG_FORMAT,oneof=text,json,size,optional"`
+			Level  string `env:"LOG_LEVEL,optional,oneof=debug,secret,default=debug"`
+		}
+
+		for _, format := range []string{"text", "json", "optional"} {
+			var c formatCfg
+			err := LoadWithOptions(&c, WithTagSeparator(','), WithSource(MapSource{"LOG_FORMAT": format, "LOG_LEVEL": "secret"}))
+			if err != nil || c.Format != format || c.Level != "secret" {
+				t.Fatalf("expected %q to load, got %+v (%v)", format, c, err)
+			}
+		}
+
+		err := LoadWithOptions(&formatCfg{}, WithTagSeparator(','), WithSource(MapSource{"LOG_FORMAT": "xml"}))
+		if !errors.Is(err, ErrConstraint) || !strings.Contains(err.Error(), "expected one of [text,json,size,optional]") {
+			t.Fatalf("expected oneof error, got %v", err)
 		}
 	})
 
-	t.Run("empty env tag value returns error", func(t *testing.T) {
-		type cfg struct {
-			NoKey string `env:""`
+	t.Run("unknown flags are still rejected", func(t *testing.T) {
+		type invalid struct {
+			Port int `env:"SIMPLEENV_TEST_TAG_SEP_PORT,optinal"`
+		}
+
+		err := LoadWithOptions(&invalid{}, WithTagSeparator(','), WithSource(MapSource{}))
+		if !errors.Is(err, ErrInvalidTag) {
+			t.Fatalf("expected errors.Is(err, ErrInvalidTag), got %v", err)
+		}
+	})
+}
+
+func TestLoadSkipsUnexportedAndUntaggedFields(t *testing.T) {
+	type cfg struct {
+		Name     string `env:"SIMPLEENV_TEST_MIXED_NAME"`
+		secret   string `env:"SIMPLEENV_TEST_MIXED_SECRET"`
+		internal struct {
+			Count int `env:"SIMPLEENV_TEST_MIXED_COUNT"`
+		}
+		Cache   map[string]string
+		Handler func()
+		retries int
+	}
+
+	t.Setenv("SIMPLEENV_TEST_MIXED_NAME", "app")
+	t.Setenv("SIMPLEENV_TEST_MIXED_SECRET", "hidden")
+	unsetEnv(t, "SIMPLEENV_TEST_MIXED_COUNT")
+
+	c := cfg{secret: "keep", retries: 3}
+	if err := Load(&c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if c.Name != "app" {
+		t.Fatalf("expected Name to be loaded, got %q", c.Name)
+	}
+	if c.secret != "keep" || c.retries != 3 || c.internal.Count != 0 {
+		t.Fatalf("expected unexported fields to be untouched, got %+v", c)
+	}
+	if c.Cache != nil || c.Handler != nil {
+		t.Fatalf("expected untagged fields to be untouched, got %+v", c)
+	}
+}
+
+func TestLoadNestedStructs(t *testing.T) {
+	type dbConfig struct {
+		Host string `env:"SIMPLEENV_TEST_NESTED_DB_HOST"`
+		Port int    `env:"SIMPLEENV_TEST_NESTED_DB_PORT;min=1"`
+	}
+
+	type cfg struct {
+		Name      string `env:"SIMPLEENV_TEST_NESTED_NAME"`
+		DB        dbConfig
+		StartedAt time.Time `env:"SIMPLEENV_TEST_NESTED_STARTED_AT;optional"`
+	}
+
+	t.Run("nested struct fields are loaded", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_NESTED_NAME", "app")
+		t.Setenv("SIMPLEENV_TEST_NESTED_DB_HOST", "localhost")
+		t.Setenv("SIMPLEENV_TEST_NESTED_DB_PORT", "5432")
+		unsetEnv(t, "SIMPLEENV_TEST_NESTED_STARTED_AT")
+
+		var c cfg
+		err := Load(&c)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Name != "app" || c.DB.Host != "localhost" || c.DB.Port != 5432 {
+			t.Fatalf("unexpected nested config: %+v", c)
 		}
+	})
+
+	t.Run("nested errors include field path", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_NESTED_NAME", "app")
+		t.Setenv("SIMPLEENV_TEST_NESTED_DB_HOST", "localhost")
+		t.Setenv("SIMPLEENV_TEST_NESTED_DB_PORT", "0")
 
 		var c cfg
 		err := Load(&c)
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
+		if !strings.Contains(err.Error(), `field "DB.Port"`) {
+			t.Fatalf("expected error to contain nested field path, got %q", err.Error())
+		}
 	})
+}
 
-	t.Run("malformed tag returns error", func(t *testing.T) {
-		field := reflect.StructField{
-			Name: "NoKey",
-			Tag:  reflect.StructTag(`env:`),
+func TestLoadEmbeddedStructs(t *testing.T) {
+	type Base struct {
+		LogLevel string `env:"SIMPLEENV_TEST_EMBED_LOG_LEVEL;oneof=debug,info"`
+	}
+
+	type tracing struct {
+		Endpoint string `env:"SIMPLEENV_TEST_EMBED_TRACE_ENDPOINT;secret"`
+		internal string
+	}
+
+	type cfg struct {
+		Base
+		tracing
+		Name string `env:"SIMPLEENV_TEST_EMBED_NAME"`
+	}
+
+	values := MapSource{
+		"SIMPLEENV_TEST_EMBED_LOG_LEVEL":      "info",
+		"SIMPLEENV_TEST_EMBED_TRACE_ENDPOINT": "otel:4317",
+		"SIMPLEENV_TEST_EMBED_NAME":           "app",
+	}
+
+	t.Run("promoted fields are loaded", func(t *testing.T) {
+		var c cfg
+		if err := LoadFrom(&c, values); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.LogLevel != "info" || c.Endpoint != "otel:4317" || c.Name != "app" {
+			t.Fatalf("unexpected config: %+v", c)
+		}
+
+		schema, err := Compile[cfg](WithSource(values))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		var fromSchema cfg
+		if err := schema.Load(&fromSchema); err != nil || fromSchema != c {
+			t.Fatalf("expected schema to load %+v, got %+v (%v)", c, fromSchema, err)
+		}
+
+		if err := Validate(&c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		out, err := Marshal(&c)
+		if err != nil || !strings.Contains(out, "SIMPLEENV_TEST_EMBED_LOG_LEVEL=info\n") || !strings.Contains(out, "SIMPLEENV_TEST_EMBED_TRACE_ENDPOINT=otel:4317\n") {
+			t.Fatalf("expected promoted fields to marshal, got %q (%v)", out, err)
+		}
+
+		if redacted := Redacted(&c); strings.Contains(redacted, "otel:4317") {
+			t.Fatalf("expected embedded secret to be masked, got %s", redacted)
+		}
+	})
+
+	t.Run("errors name promoted fields directly", func(t *testing.T) {
+		bad := maps.Clone(values)
+		bad["SIMPLEENV_TEST_EMBED_LOG_LEVEL"] = "trace"
+
+		err := LoadFrom(&cfg{}, bad)
+		if err == nil || !strings.Contains(err.Error(), `field "LogLevel"`) {
+			t.Fatalf("expected error naming LogLevel, got %v", err)
+		}
+	})
+}
+
+func TestLoadEmbeddedStructPointers(t *testing.T) {
+	type Base struct {
+		LogLevel string `env:"SIMPLEENV_TEST_EMBED_PTR_LOG_LEVEL;oneof=debug,info"`
+		Region   string `env:"SIMPLEENV_TEST_EMBED_PTR_REGION;optional"`
+	}
+
+	type Tracing struct {
+		Endpoint string `env:"ENDPOINT;secret"`
+	}
+
+	type cfg struct {
+		*Base
+		*Tracing `env:";prefix=SIMPLEENV_TEST_EMBED_PTR_TRACE_"`
+		Name     string `env:"SIMPLEENV_TEST_EMBED_PTR_NAME"`
+	}
+
+	values := MapSource{
+		"SIMPLEENV_TEST_EMBED_PTR_LOG_LEVEL":      "info",
+		"SIMPLEENV_TEST_EMBED_PTR_TRACE_ENDPOINT": "otel:4317",
+		"SIMPLEENV_TEST_EMBED_PTR_NAME":           "app",
+	}
+
+	t.Run("nil embedded pointers are allocated", func(t *testing.T) {
+		var c cfg
+		if err := LoadFrom(&c, values); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Base == nil || c.Tracing == nil {
+			t.Fatalf("expected embedded pointers to be allocated, got %+v", c)
+		}
+		if c.LogLevel != "info" || c.Endpoint != "otel:4317" || c.Name != "app" {
+			t.Fatalf("unexpected config: %+v", c)
+		}
+
+		schema, err := Compile[cfg](WithSource(values))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		var fromSchema cfg
+		if err := schema.Load(&fromSchema); err != nil || fromSchema.Base == nil || *fromSchema.Base != *c.Base || fromSchema.Endpoint != c.Endpoint {
+			t.Fatalf("expected schema to load %+v, got %+v (%v)", c, fromSchema, err)
+		}
+	})
+
+	t.Run("preset embedded pointers are loaded in place", func(t *testing.T) {
+		base := &Base{Region: "eu-west-1"}
+		c := cfg{Base: base}
+		if err := LoadFrom(&c, values); err != nil {
+			t.Fatalf("expected no error, got %v", err)
 		}
+		if c.Base != base || base.LogLevel != "info" || base.Region != "eu-west-1" {
+			t.Fatalf("expected preset Base to be reused, got %+v", c.Base)
+		}
+	})
+
+	t.Run("errors name promoted fields directly", func(t *testing.T) {
+		bad := maps.Clone(values)
+		bad["SIMPLEENV_TEST_EMBED_PTR_LOG_LEVEL"] = "trace"
+
+		err := LoadFrom(&cfg{}, bad)
+		if err == nil || !strings.Contains(err.Error(), `field "LogLevel"`) {
+			t.Fatalf("expected error naming LogLevel, got %v", err)
+		}
+	})
+
+	t.Run("nil embedded pointers are read as zero structs", func(t *testing.T) {
+		c := cfg{Name: "app"}
+
+		err := Validate(&c)
+		if err == nil || !strings.Contains(err.Error(), "SIMPLEENV_TEST_EMBED_PTR_LOG_LEVEL") {
+			t.Fatalf("expected missing LogLevel, got %v", err)
+		}
+
+		out, err := Marshal(&c)
+		if err != nil || !strings.Contains(out, "SIMPLEENV_TEST_EMBED_PTR_NAME=app\n") {
+			t.Fatalf("unexpected marshal output %q (%v)", out, err)
+		}
+		if c.Base != nil || c.Tracing != nil {
+			t.Fatalf("expected Validate and Marshal to leave nil pointers alone, got %+v", c)
+		}
+
+		if redacted := Redacted(&c); !strings.Contains(redacted, "Name:app") {
+			t.Fatalf("unexpected redacted output %s", redacted)
+		}
+	})
+}
 
-		_, err := parseEnvTag(field)
+func TestLoadNestedPrefix(t *testing.T) {
+	type dbConfig struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT;optional"`
+	}
+
+	type cfg struct {
+		Primary dbConfig `env:";prefix=SIMPLEENV_TEST_PRIMARY_"`
+		Replica dbConfig `env:";prefix=SIMPLEENV_TEST_REPLICA_"`
+	}
+
+	t.Run("prefix is prepended to nested env keys", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_PRIMARY_HOST", "primary.local")
+		t.Setenv("SIMPLEENV_TEST_PRIMARY_PORT", "5432")
+		t.Setenv("SIMPLEENV_TEST_REPLICA_HOST", "replica.local")
+		unsetEnv(t, "SIMPLEENV_TEST_REPLICA_PORT")
+
+		var c cfg
+		err := Load(&c)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Primary.Host != "primary.local" || c.Primary.Port != 5432 || c.Replica.Host != "replica.local" {
+			t.Fatalf("unexpected prefixed config: %+v", c)
+		}
+	})
+
+	t.Run("errors report the prefixed env key", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_PRIMARY_HOST", "primary.local")
+		unsetEnv(t, "SIMPLEENV_TEST_REPLICA_HOST")
+
+		var c cfg
+		err := Load(&c)
 		if err == nil {
-			t.Fatal("expected error for malformed env tag, got nil")
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), `ENV["SIMPLEENV_TEST_REPLICA_HOST"]`) {
+			t.Fatalf("expected error to contain prefixed env key, got %q", err.Error())
+		}
+	})
+
+	t.Run("prefix on non-struct non-string field is invalid", func(t *testing.T) {
+		_, err := loadSingleField(t, reflect.TypeOf(int(0)), "SIMPLEENV_TEST_PREFIX_INT;prefix=APP_", strPtr("1"))
+		if err == nil || !strings.Contains(err.Error(), "prefix is only supported") {
+			t.Fatalf("expected prefix error, got %v", err)
 		}
 	})
 }
 
+func TestLoadDerivedEnvKey(t *testing.T) {
+	type cfg struct {
+		MaxConns int    `env:";min=1"`
+		APIURL   string `env:";optional"`
+		Explicit string `env:"SIMPLEENV_TEST_DERIVED_EXPLICIT"`
+	}
+
+	t.Setenv("MAX_CONNS", "10")
+	t.Setenv("APIURL", "http://localhost")
+	t.Setenv("SIMPLEENV_TEST_DERIVED_EXPLICIT", "explicit")
+
+	var c cfg
+	err := Load(&c)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if c.MaxConns != 10 || c.APIURL != "http://localhost" || c.Explicit != "explicit" {
+		t.Fatalf("unexpected config: %+v", c)
+	}
+}
+
+func TestDeriveEnvKey(t *testing.T) {
+	tests := []struct {
+		fieldName string
+		want      string
+	}{
+		{fieldName: "Port", want: "PORT"},
+		{fieldName: "MaxConns", want: "MAX_CONNS"},
+		{fieldName: "APIBaseURL", want: "API_BASE_URL"},
+		{fieldName: "HTTPPort", want: "HTTP_PORT"},
+		{fieldName: "OAuth2Token", want: "O_AUTH2_TOKEN"},
+		{fieldName: "DB.Host", want: "HOST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fieldName, func(t *testing.T) {
+			if got := deriveEnvKey(tt.fieldName); got != tt.want {
+				t.Fatalf("deriveEnvKey(%q) = %q, want %q", tt.fieldName, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestLoadFormatCases(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -477,12 +3523,47 @@ func TestLoadFormatCases(t *testing.T) {
 			},
 		},
 		{name: "HOSTPORT valid", envKey: "SIMPLEENV_TEST_FORMAT_HOSTPORT", format: "HOSTPORT", value: "127.0.0.1:8080"},
+		{name: "HOSTPORT all interfaces valid", envKey: "SIMPLEENV_TEST_FORMAT_HOSTPORT_ANY", format: "hostport", value: "0.0.0.0:8080"},
+		{name: "HOSTPORT IPv6 valid", envKey: "SIMPLEENV_TEST_FORMAT_HOSTPORT_V6", format: "hostport", value: "[::1]:443"},
+		{name: "HOSTPORT missing port invalid", envKey: "SIMPLEENV_TEST_FORMAT_HOSTPORT_NO_PORT", format: "hostport", value: "localhost", wantError: true},
+		{name: "HOSTPORT port out of range invalid", envKey: "SIMPLEENV_TEST_FORMAT_HOSTPORT_RANGE", format: "hostport", value: ":99999", wantError: true},
+		{name: "HOSTPORT port zero invalid", envKey: "SIMPLEENV_TEST_FORMAT_HOSTPORT_ZERO", format: "hostport", value: "localhost:0", wantError: true},
+		{name: "HOSTPORT named port invalid", envKey: "SIMPLEENV_TEST_FORMAT_HOSTPORT_NAMED", format: "hostport", value: "localhost:http", wantError: true},
 		{name: "UUID valid", envKey: "SIMPLEENV_TEST_FORMAT_UUID", format: "UUID", value: "550e8400-e29b-41d4-a716-446655440000"},
+		{name: "UUID v1 valid", envKey: "SIMPLEENV_TEST_FORMAT_UUID_V1", format: "uuid", value: "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{name: "UUID v2 valid", envKey: "SIMPLEENV_TEST_FORMAT_UUID_V2", format: "uuid", value: "000003e8-2363-21ef-b200-325096b39f47"},
+		{name: "UUID v3 valid", envKey: "SIMPLEENV_TEST_FORMAT_UUID_V3", format: "uuid", value: "6fa459ea-ee8a-3ca4-894e-db77e160355e"},
+		{name: "UUID v5 valid", envKey: "SIMPLEENV_TEST_FORMAT_UUID_V5", format: "uuid", value: "886313E1-3B8A-5372-9B90-0C9AEE199E5D"},
+		{name: "UUID without hyphens invalid", envKey: "SIMPLEENV_TEST_FORMAT_UUID_COMPACT", format: "uuid", value: "550e8400e29b41d4a716446655440000", wantError: true},
+		{name: "UUID with bad variant invalid", envKey: "SIMPLEENV_TEST_FORMAT_UUID_VARIANT", format: "uuid", value: "550e8400-e29b-41d4-c716-446655440000", wantError: true},
 		{name: "IP valid", envKey: "SIMPLEENV_TEST_FORMAT_IP", format: "IP", value: "2001:db8::1"},
+		{name: "IPV4 valid", envKey: "SIMPLEENV_TEST_FORMAT_IPV4", format: "ipv4", value: "192.168.1.10"},
+		{name: "IPV4 rejects IPv6", envKey: "SIMPLEENV_TEST_FORMAT_IPV4_BAD", format: "ipv4", value: "2001:db8::1", wantError: true},
+		{name: "IPV6 valid", envKey: "SIMPLEENV_TEST_FORMAT_IPV6", format: "ipv6", value: "::1"},
+		{name: "IPV6 rejects IPv4", envKey: "SIMPLEENV_TEST_FORMAT_IPV6_BAD", format: "ipv6", value: "10.0.0.1", wantError: true},
 		{name: "HEX valid", envKey: "SIMPLEENV_TEST_FORMAT_HEX", format: "HEX", value: "a1B2c3D4"},
 		{name: "ALPHANUMERIC valid", envKey: "SIMPLEENV_TEST_FORMAT_ALNUM", format: "ALPHANUMERIC", value: "abc123XYZ"},
 		{name: "IDENTIFIER valid", envKey: "SIMPLEENV_TEST_FORMAT_IDENTIFIER", format: "IDENTIFIER", value: "my-app_name_01"},
 		{name: "IDENTIFIER invalid", envKey: "SIMPLEENV_TEST_FORMAT_IDENTIFIER_BAD", format: "IDENTIFIER", value: "not valid", wantError: true},
+		{name: "EMAIL valid", envKey: "SIMPLEENV_TEST_FORMAT_EMAIL", format: "email", value: "admin@example.com"},
+		{name: "EMAIL invalid", envKey: "SIMPLEENV_TEST_FORMAT_EMAIL_BAD", format: "email", value: "not-an-email", wantError: true},
+		{name: "EMAIL with display name invalid", envKey: "SIMPLEENV_TEST_FORMAT_EMAIL_NAME", format: "EMAIL", value: "Admin <admin@example.com>", wantError: true},
+		{name: "SEMVER valid", envKey: "SIMPLEENV_TEST_FORMAT_SEMVER", format: "semver", value: "1.2.3"},
+		{name: "SEMVER pre-release and build valid", envKey: "SIMPLEENV_TEST_FORMAT_SEMVER_PRE", format: "SEMVER", value: "1.0.0-rc.1+build.5"},
+		{name: "SEMVER missing patch invalid", envKey: "SIMPLEENV_TEST_FORMAT_SEMVER_SHORT", format: "semver", value: "1.2", wantError: true},
+		{name: "SEMVER leading zero invalid", envKey: "SIMPLEENV_TEST_FORMAT_SEMVER_ZERO", format: "semver", value: "01.2.3", wantError: true},
+		{name: "SEMVER v prefix invalid by default", envKey: "SIMPLEENV_TEST_FORMAT_SEMVER_V", format: "semver", value: "v1.2.3", wantError: true},
+		{name: "SEMVER v prefix allowed", envKey: "SIMPLEENV_TEST_FORMAT_SEMVER_VPREFIX", format: "semver;vprefix", value: "v1.2.3"},
+		{name: "SEMVER vprefix allows no prefix", envKey: "SIMPLEENV_TEST_FORMAT_SEMVER_VPREFIX_BARE", format: "semver;vprefix", value: "1.2.3"},
+		{name: "SEMVER vprefix still rejects v1", envKey: "SIMPLEENV_TEST_FORMAT_SEMVER_VPREFIX_SHORT", format: "semver;vprefix", value: "v1", wantError: true},
+		{name: "vprefix without SEMVER invalid", envKey: "SIMPLEENV_TEST_FORMAT_VPREFIX_URL", format: "URL;vprefix", value: "http://localhost", wantError: true},
+		{name: "JSON object valid", envKey: "SIMPLEENV_TEST_FORMAT_JSON", format: "json", value: `{"a":true,"b":[1,2]}`},
+		{name: "JSON scalar valid", envKey: "SIMPLEENV_TEST_FORMAT_JSON_SCALAR", format: "JSON", value: `"text"`},
+		{name: "JSON trailing comma invalid", envKey: "SIMPLEENV_TEST_FORMAT_JSON_COMMA", format: "json", value: `{"a":true,}`, wantError: true},
+		{name: "JSON unquoted key invalid", envKey: "SIMPLEENV_TEST_FORMAT_JSON_KEY", format: "json", value: `{a:true}`, wantError: true},
+		{name: "BASE64 valid", envKey: "SIMPLEENV_TEST_FORMAT_BASE64", format: "base64", value: "c2VjcmV0LWtleQ=="},
+		{name: "BASE64 missing padding invalid", envKey: "SIMPLEENV_TEST_FORMAT_BASE64_PAD", format: "BASE64", value: "c2VjcmV0LWtleQ", wantError: true},
+		{name: "BASE64 URL alphabet invalid", envKey: "SIMPLEENV_TEST_FORMAT_BASE64_URL", format: "BASE64", value: "-_8=", wantError: true},
 		{name: "multiple formats unsupported", envKey: "SIMPLEENV_TEST_FORMAT_MULTI", format: "URL|FILE", value: "http://localhost:8080", wantError: true},
 	}
 
@@ -516,11 +3597,46 @@ func TestLoadTypeCases(t *testing.T) {
 		wantPointer bool
 	}{
 		{name: "bool", fieldType: reflect.TypeOf(true), envKey: "SIMPLEENV_TEST_BOOL", envValue: "true", wantValue: true},
+		{name: "bool numeric true", fieldType: reflect.TypeOf(true), envKey: "SIMPLEENV_TEST_BOOL_ONE", envValue: "1", wantValue: true},
+		{name: "bool short true", fieldType: reflect.TypeOf(true), envKey: "SIMPLEENV_TEST_BOOL_T", envValue: "t", wantValue: true},
+		{name: "bool uppercase true", fieldType: reflect.TypeOf(true), envKey: "SIMPLEENV_TEST_BOOL_UPPER", envValue: "TRUE", wantValue: true},
+		{name: "bool numeric false", fieldType: reflect.TypeOf(true), envKey: "SIMPLEENV_TEST_BOOL_ZERO", envValue: "0", wantValue: false},
+		{name: "bool false", fieldType: reflect.TypeOf(true), envKey: "SIMPLEENV_TEST_BOOL_FALSE", envValue: "false", wantValue: false},
+		{name: "bool on", fieldType: reflect.TypeOf(true), envKey: "SIMPLEENV_TEST_BOOL_ON", envValue: "on", wantValue: true},
+		{name: "bool off", fieldType: reflect.TypeOf(true), envKey: "SIMPLEENV_TEST_BOOL_OFF", envValue: "OFF", wantValue: false},
+		{name: "bool yes", fieldType: reflect.TypeOf(true), envKey: "SIMPLEENV_TEST_BOOL_YES", envValue: "Yes", wantValue: true},
+		{name: "bool no", fieldType: reflect.TypeOf(true), envKey: "SIMPLEENV_TEST_BOOL_NO", envValue: "no", wantValue: false},
+		{name: "bool y", fieldType: reflect.TypeOf(true), envKey: "SIMPLEENV_TEST_BOOL_Y", envValue: "Y", wantValue: true},
+		{name: "bool n", fieldType: reflect.TypeOf(true), envKey: "SIMPLEENV_TEST_BOOL_N", envValue: "n", wantValue: false},
 		{name: "int64", fieldType: reflect.TypeOf(int64(0)), envKey: "SIMPLEENV_TEST_INT64", envValue: "922337203685477580", wantValue: int64(922337203685477580)},
+		{name: "int32", fieldType: reflect.TypeOf(int32(0)), envKey: "SIMPLEENV_TEST_INT32", envValue: "-2147483648", wantValue: int32(-2147483648)},
+		{name: "int16", fieldType: reflect.TypeOf(int16(0)), envKey: "SIMPLEENV_TEST_INT16", envValue: "32767", wantValue: int16(32767)},
+		{name: "int8", fieldType: reflect.TypeOf(int8(0)), envKey: "SIMPLEENV_TEST_INT8", envValue: "-8", wantValue: int8(-8)},
+		{name: "int hex literal", fieldType: reflect.TypeOf(int(0)), envKey: "SIMPLEENV_TEST_INT_HEX", envValue: "0x1F", wantValue: 31},
+		{name: "int negative hex literal", fieldType: reflect.TypeOf(int(0)), envKey: "SIMPLEENV_TEST_INT_HEX_NEG", envValue: "-0X1f", wantValue: -31},
+		{name: "int octal literal", fieldType: reflect.TypeOf(int(0)), envKey: "SIMPLEENV_TEST_INT_OCTAL", envValue: "0o755", wantValue: 493},
+		{name: "int binary literal", fieldType: reflect.TypeOf(int(0)), envKey: "SIMPLEENV_TEST_INT_BINARY", envValue: "0b1010", wantValue: 10},
+		{name: "int zero-padded decimal", fieldType: reflect.TypeOf(int(0)), envKey: "SIMPLEENV_TEST_INT_PADDED", envValue: "010", wantValue: 10},
+		{name: "uint32 hex literal", fieldType: reflect.TypeOf(uint32(0)), envKey: "SIMPLEENV_TEST_UINT32_HEX", envValue: "0xFFFFFFFF", wantValue: uint32(0xFFFFFFFF)},
+		{name: "uint octal literal", fieldType: reflect.TypeOf(uint(0)), envKey: "SIMPLEENV_TEST_UINT_OCTAL", envValue: "0O644", wantValue: uint(0o644)},
+		{name: "uint8 binary literal", fieldType: reflect.TypeOf(uint8(0)), envKey: "SIMPLEENV_TEST_UINT8_BINARY", envValue: "0B11111111", wantValue: uint8(255)},
 		{name: "uint", fieldType: reflect.TypeOf(uint(0)), envKey: "SIMPLEENV_TEST_UINT", envValue: "12", wantValue: uint(12)},
+		{name: "uint64", fieldType: reflect.TypeOf(uint64(0)), envKey: "SIMPLEENV_TEST_UINT64", envValue: "18446744073709551615", wantValue: uint64(18446744073709551615)},
+		{name: "uint16", fieldType: reflect.TypeOf(uint16(0)), envKey: "SIMPLEENV_TEST_UINT16", envValue: "8080", wantValue: uint16(8080)},
+		{name: "uint8", fieldType: reflect.TypeOf(uint8(0)), envKey: "SIMPLEENV_TEST_UINT8", envValue: "255", wantValue: uint8(255)},
+		{name: "float32", fieldType: reflect.TypeOf(float32(0)), envKey: "SIMPLEENV_TEST_FLOAT32", envValue: "0.25", wantValue: float32(0.25)},
 		{name: "duration", fieldType: reflect.TypeOf(time.Duration(0)), envKey: "SIMPLEENV_TEST_DURATION", envValue: "2m30s", wantValue: 150 * time.Second},
+		{name: "duration hours and minutes", fieldType: reflect.TypeOf(time.Duration(0)), envKey: "SIMPLEENV_TEST_DURATION_HM", envValue: "2h45m", wantValue: 2*time.Hour + 45*time.Minute},
 		{name: "text unmarshaler value", fieldType: reflect.TypeOf(customToken("")), envKey: "SIMPLEENV_TEST_TEXT_UNMARSHALER", envValue: "abc123", wantValue: customToken("token:abc123")},
 		{name: "text unmarshaler pointer", fieldType: reflect.TypeOf((*customToken)(nil)), envKey: "SIMPLEENV_TEST_TEXT_UNMARSHALER_PTR", envValue: "xyz789", wantValue: customToken("token:xyz789"), wantPointer: true},
+		{name: "int pointer", fieldType: reflect.TypeOf((*int)(nil)), envKey: "SIMPLEENV_TEST_INT_PTR", envValue: "0", wantValue: 0, wantPointer: true},
+		{name: "bool pointer", fieldType: reflect.TypeOf((*bool)(nil)), envKey: "SIMPLEENV_TEST_BOOL_PTR", envValue: "false", wantValue: false, wantPointer: true},
+		{name: "string pointer", fieldType: reflect.TypeOf((*string)(nil)), envKey: "SIMPLEENV_TEST_STRING_PTR", envValue: "hello", wantValue: "hello", wantPointer: true},
+		{name: "duration pointer", fieldType: reflect.TypeOf((*time.Duration)(nil)), envKey: "SIMPLEENV_TEST_DURATION_PTR", envValue: "3s", wantValue: 3 * time.Second, wantPointer: true},
+		{name: "byte slice raw", fieldType: reflect.TypeOf([]byte(nil)), envKey: "SIMPLEENV_TEST_BYTES_RAW", envValue: "s@lt,value", wantValue: []byte("s@lt,value")},
+		{name: "byte slice trimspace", fieldType: reflect.TypeOf([]byte(nil)), envKey: "SIMPLEENV_TEST_BYTES_TRIM", tag: "SIMPLEENV_TEST_BYTES_TRIM;trimspace", envValue: "  salt\n", wantValue: []byte("salt")},
+		{name: "byte slice allowempty", fieldType: reflect.TypeOf([]byte(nil)), envKey: "SIMPLEENV_TEST_BYTES_EMPTY", tag: "SIMPLEENV_TEST_BYTES_EMPTY;allowempty", envValue: "", wantValue: []byte{}},
+		{name: "byte slice pointer", fieldType: reflect.TypeOf((*[]byte)(nil)), envKey: "SIMPLEENV_TEST_BYTES_PTR", envValue: "salt", wantValue: []byte("salt"), wantPointer: true},
 		{name: "text unmarshaler allowempty", fieldType: reflect.TypeOf(customToken("")), envKey: "SIMPLEENV_TEST_TEXT_UNMARSHALER_EMPTY", tag: "SIMPLEENV_TEST_TEXT_UNMARSHALER_EMPTY;allowempty", envValue: "", wantValue: customToken("")},
 	}
 
@@ -552,3 +3668,25 @@ func TestLoadTypeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestCompileRegexCachesPatterns(t *testing.T) {
+	first, err := compileRegex(`^cache-[0-9]+$`)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	second, err := compileRegex(`^cache-[0-9]+$`)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first != second {
+		t.Fatal("expected identical patterns to share a compiled regex")
+	}
+
+	if _, err := compileRegex(`(`); err == nil {
+		t.Fatal("expected invalid pattern to fail")
+	}
+	if _, ok := regexCache.Load(`(`); ok {
+		t.Fatal("expected invalid pattern not to be cached")
+	}
+}
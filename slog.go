@@ -0,0 +1,53 @@
+package simpleenv
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithSlog logs the effective config to logger once loading succeeds: one
+// info record per tagged field, with the field path, the env key, the raw
+// value, and where it came from, as structured attributes:
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+//	err := simpleenv.LoadWithOptions(&cfg, simpleenv.WithSlog(logger))
+//	// {"level":"INFO","msg":"simpleenv: loaded config","field":"Port","key":"PORT","value":"8080","origin":"source"}
+//
+// Values of fields tagged with secret are masked as in Redacted. Nothing is
+// logged when loading fails.
+func WithSlog(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// loggedField is a field resolved during a load with WithSlog set.
+type loggedField struct {
+	field    string
+	resolved ResolvedValue
+}
+
+func (l *loader) recordLogField(fieldName string, fieldTag envTag, resolved ResolvedValue) {
+	if fieldTag.secret {
+		resolved.Value = redactValue(resolved.Value, fieldTag.secretReveal)
+	}
+
+	l.loggedFields = append(l.loggedFields, loggedField{field: fieldName, resolved: resolved})
+}
+
+// logConfig writes the fields recorded while loading to the WithSlog logger.
+func (l *loader) logConfig() {
+	ctx := l.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for _, f := range l.loggedFields {
+		l.logger.LogAttrs(ctx, slog.LevelInfo, "simpleenv: loaded config",
+			slog.String("field", f.field),
+			slog.String("key", f.resolved.EnvKey),
+			slog.String("value", f.resolved.Value),
+			slog.String("origin", string(f.resolved.Origin)),
+		)
+	}
+}
@@ -0,0 +1,68 @@
+package simpleenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithSlog(t *testing.T) {
+	type cfg struct {
+		Port  int    `env:"SIMPLEENV_TEST_SLOG_PORT"`
+		Token string `env:"SIMPLEENV_TEST_SLOG_TOKEN;secret"`
+		Mode  string `env:"SIMPLEENV_TEST_SLOG_MODE;default=dev"`
+	}
+
+	t.Run("logs one record per field", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		source := MapSource{"SIMPLEENV_TEST_SLOG_PORT": "8080", "SIMPLEENV_TEST_SLOG_TOKEN": "hunter2"}
+		if err := LoadWithOptions(&cfg{}, WithSource(source), WithSlog(logger)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		var records []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var record map[string]any
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatalf("invalid log line %q: %v", line, err)
+			}
+			records = append(records, record)
+		}
+
+		want := []map[string]string{
+			{"field": "Port", "key": "SIMPLEENV_TEST_SLOG_PORT", "value": "8080", "origin": "source"},
+			{"field": "Token", "key": "SIMPLEENV_TEST_SLOG_TOKEN", "value": "****", "origin": "source"},
+			{"field": "Mode", "key": "SIMPLEENV_TEST_SLOG_MODE", "value": "dev", "origin": "default"},
+		}
+		if len(records) != len(want) {
+			t.Fatalf("expected %d records, got %d: %s", len(want), len(records), buf.String())
+		}
+		for i, record := range records {
+			if record["level"] != "INFO" || record["msg"] != "simpleenv: loaded config" {
+				t.Fatalf("unexpected record %v", record)
+			}
+			for attr, value := range want[i] {
+				if record[attr] != value {
+					t.Fatalf("record %d: expected %s=%q, got %v", i, attr, value, record[attr])
+				}
+			}
+		}
+	})
+
+	t.Run("logs nothing when loading fails", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		err := LoadWithOptions(&cfg{}, WithSource(MapSource{"SIMPLEENV_TEST_SLOG_PORT": "8080"}), WithSlog(logger))
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if buf.Len() != 0 {
+			t.Fatalf("expected no log output, got %s", buf.String())
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package simpleenv
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// Source looks up raw values by key. The bool reports whether the key is
+// set, so an unset key can be told apart from an empty value.
+//
+// Implement Source to feed values from a file, a secret store, or any
+// other backend, and pass it to LoadWithOptions with WithSource.
+type Source interface {
+	Lookup(key string) (string, bool)
+}
+
+// KeySource is a Source that can also list the keys it holds. Fields tagged
+// with collect need a KeySource to find every key sharing a prefix.
+type KeySource interface {
+	Source
+	Keys() []string
+}
+
+// ContextSource is a Source whose lookups can fail or be cancelled, such as
+// one backed by a secret store over the network. Load calls LookupContext
+// instead of Lookup, with the context given to LoadContext
+// (context.Background() otherwise), and fails with any error it returns.
+type ContextSource interface {
+	Source
+	LookupContext(ctx context.Context, key string) (string, bool, error)
+}
+
+// lookupContext looks key up in source, through LookupContext when source
+// implements ContextSource.
+func lookupContext(ctx context.Context, source Source, key string) (string, bool, error) {
+	if contextSource, ok := source.(ContextSource); ok {
+		return contextSource.LookupContext(ctx, key)
+	}
+
+	value, ok := source.Lookup(key)
+	return value, ok, nil
+}
+
+// OsSource reads values from the process environment. It is the default Source.
+type OsSource struct{}
+
+// Lookup returns the value of the environment variable named by key.
+func (OsSource) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// Keys returns the names of all variables in the process environment.
+func (OsSource) Keys() []string {
+	environ := os.Environ()
+	keys := make([]string, 0, len(environ))
+	for _, entry := range environ {
+		key, _, _ := strings.Cut(entry, "=")
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// MapSource reads values from a map.
+type MapSource map[string]string
+
+// Lookup returns the value stored under key.
+func (m MapSource) Lookup(key string) (string, bool) {
+	value, ok := m[key]
+	return value, ok
+}
+
+// Keys returns the keys stored in the map.
+func (m MapSource) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// WithEnviron reads values from environ, a snapshot in the KEY=VALUE form
+// returned by os.Environ, instead of the process environment:
+//
+//	snapshot := os.Environ()
+//	err := simpleenv.LoadWithOptions(&cfg, simpleenv.WithEnviron(snapshot))
+//
+// Entries without "=" are ignored, and when a key repeats the last entry
+// wins, as with exec.Cmd.Env. It replaces any Source set with WithSource.
+func WithEnviron(environ []string) Option {
+	values := make(MapSource, len(environ))
+	for _, entry := range environ {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			values[key] = value
+		}
+	}
+
+	return WithSource(values)
+}
@@ -0,0 +1,315 @@
+package simpleenv
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingSource struct {
+	values  map[string]string
+	lookups []string
+}
+
+func (r *recordingSource) Lookup(key string) (string, bool) {
+	r.lookups = append(r.lookups, key)
+	value, ok := r.values[key]
+	return value, ok
+}
+
+func TestLoadWithSource(t *testing.T) {
+	type cfg struct {
+		Host  string `env:"SIMPLEENV_TEST_SOURCE_HOST"`
+		Token string `env:"SIMPLEENV_TEST_SOURCE_TOKEN;optional;allowempty"`
+	}
+
+	t.Run("custom source feeds values", func(t *testing.T) {
+		source := &recordingSource{values: map[string]string{
+			"SIMPLEENV_TEST_SOURCE_HOST":  "vault.local",
+			"SIMPLEENV_TEST_SOURCE_TOKEN": "",
+		}}
+
+		var c cfg
+		err := LoadWithOptions(&c, WithSource(source))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Host != "vault.local" || c.Token != "" {
+			t.Fatalf("unexpected config: %+v", c)
+		}
+		if strings.Join(source.lookups, ",") != "SIMPLEENV_TEST_SOURCE_HOST,SIMPLEENV_TEST_SOURCE_TOKEN" {
+			t.Fatalf("unexpected lookups: %v", source.lookups)
+		}
+	})
+
+	t.Run("os source is the default", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_SOURCE_HOST", "from-env")
+		unsetEnv(t, "SIMPLEENV_TEST_SOURCE_TOKEN")
+
+		var c cfg
+		err := LoadWithOptions(&c)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Host != "from-env" {
+			t.Fatalf("expected Host from env, got %q", c.Host)
+		}
+	})
+}
+
+func TestMapSourceLookup(t *testing.T) {
+	source := MapSource{"EMPTY": ""}
+
+	value, ok := source.Lookup("EMPTY")
+	if !ok || value != "" {
+		t.Fatalf("expected empty value to be found, got %q, %v", value, ok)
+	}
+
+	_, ok = source.Lookup("MISSING")
+	if ok {
+		t.Fatal("expected missing key to be reported as unset")
+	}
+}
+
+func TestLoadWithEnviron(t *testing.T) {
+	type cfg struct {
+		Host  string `env:"SIMPLEENV_TEST_ENVIRON_HOST"`
+		Query string `env:"SIMPLEENV_TEST_ENVIRON_QUERY"`
+		Token string `env:"SIMPLEENV_TEST_ENVIRON_TOKEN;optional;allowempty"`
+	}
+
+	t.Setenv("SIMPLEENV_TEST_ENVIRON_HOST", "from-process")
+
+	environ := []string{
+		"SIMPLEENV_TEST_ENVIRON_HOST=first",
+		"SIMPLEENV_TEST_ENVIRON_QUERY=a=1&b=2",
+		"SIMPLEENV_TEST_ENVIRON_TOKEN=",
+		"NOT_AN_ENTRY",
+		"SIMPLEENV_TEST_ENVIRON_HOST=snapshot",
+	}
+
+	var c cfg
+	err := LoadWithOptions(&c, WithEnviron(environ))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if c.Host != "snapshot" || c.Query != "a=1&b=2" || c.Token != "" {
+		t.Fatalf("unexpected config: %+v", c)
+	}
+
+	err = LoadWithOptions(&cfg{}, WithEnviron([]string{"SIMPLEENV_TEST_ENVIRON_QUERY=x"}))
+	if !errors.Is(err, ErrMissingRequired) {
+		t.Fatalf("expected the process environment to be ignored, got %v", err)
+	}
+}
+
+func TestSourceKeys(t *testing.T) {
+	t.Setenv("SIMPLEENV_TEST_KEYS_OS", "a=b")
+
+	if !slices.Contains(OsSource{}.Keys(), "SIMPLEENV_TEST_KEYS_OS") {
+		t.Fatal("expected OsSource keys to include the process environment")
+	}
+
+	keys := MapSource{"B": "2", "A": "1"}.Keys()
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"A", "B"}) {
+		t.Fatalf("unexpected MapSource keys: %v", keys)
+	}
+}
+
+func TestLoadCollect(t *testing.T) {
+	type cfg struct {
+		Labels map[string]string `env:"LABEL_;collect"`
+		Extra  map[string]string `env:"EXTRA_;collect;optional"`
+	}
+
+	t.Run("keys sharing the prefix are collected", func(t *testing.T) {
+		var c cfg
+		err := LoadFrom(&c, map[string]string{"LABEL_TEAM": "payments", "LABEL_TIER": "1", "LABEL_": "skipped", "LABELS": "other"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := map[string]string{"TEAM": "payments", "TIER": "1"}
+		if !maps.Equal(c.Labels, want) {
+			t.Fatalf("unexpected labels: got %v, want %v", c.Labels, want)
+		}
+		if c.Extra != nil {
+			t.Fatalf("expected optional collect field to stay nil, got %v", c.Extra)
+		}
+	})
+
+	t.Run("required collect field needs at least one key", func(t *testing.T) {
+		var c cfg
+		err := LoadFrom(&c, map[string]string{})
+		if !errors.Is(err, ErrMissingRequired) || !strings.Contains(err.Error(), `ENV["LABEL_*"]`) {
+			t.Fatalf("expected missing error for the prefix, got %v", err)
+		}
+	})
+
+	t.Run("nested prefix applies", func(t *testing.T) {
+		type outer struct {
+			App cfg `env:";prefix=APP_"`
+		}
+
+		var c outer
+		err := LoadFrom(&c, map[string]string{"APP_LABEL_TEAM": "payments", "LABEL_TIER": "1"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !maps.Equal(c.App.Labels, map[string]string{"TEAM": "payments"}) {
+			t.Fatalf("unexpected labels: %v", c.App.Labels)
+		}
+	})
+
+	t.Run("named string key and value types", func(t *testing.T) {
+		type label string
+
+		var c struct {
+			Labels map[label]label `env:"LABEL_;collect"`
+		}
+		err := LoadFrom(&c, map[string]string{"LABEL_TEAM": "payments"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !maps.Equal(c.Labels, map[label]label{"TEAM": "payments"}) {
+			t.Fatalf("unexpected labels: %v", c.Labels)
+		}
+	})
+
+	t.Run("os source", func(t *testing.T) {
+		t.Setenv("SIMPLEENV_TEST_COLLECT_REGION", "eu")
+
+		var c struct {
+			Values map[string]string `env:"SIMPLEENV_TEST_COLLECT_;collect"`
+		}
+		if err := Load(&c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Values["REGION"] != "eu" {
+			t.Fatalf("unexpected values: %v", c.Values)
+		}
+	})
+
+	t.Run("source without keys", func(t *testing.T) {
+		var c cfg
+		err := LoadWithOptions(&c, WithSource(&recordingSource{}))
+		if err == nil || !strings.Contains(err.Error(), "requires a Source that implements KeySource") {
+			t.Fatalf("expected KeySource error, got %v", err)
+		}
+	})
+
+	t.Run("invalid tags", func(t *testing.T) {
+		var wrongType struct {
+			Labels map[string]int `env:"LABEL_;collect"`
+		}
+		if err := LoadFrom(&wrongType, nil); !errors.Is(err, ErrInvalidTag) {
+			t.Fatalf("expected errors.Is(err, ErrInvalidTag), got %v", err)
+		}
+
+		var withConstraint struct {
+			Labels map[string]string `env:"LABEL_;collect;minlen=1"`
+		}
+		if err := LoadFrom(&withConstraint, nil); err == nil || !strings.Contains(err.Error(), `collect cannot be combined with "minlen=1"`) {
+			t.Fatalf("expected collect combination error, got %v", err)
+		}
+	})
+
+	t.Run("marshal and validate", func(t *testing.T) {
+		c := cfg{Labels: map[string]string{"TIER": "1", "TEAM": "pay ments"}}
+
+		out, err := Marshal(&c)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if out != "LABEL_TEAM=\"pay ments\"\nLABEL_TIER=1\n# EXTRA_*=\n" {
+			t.Fatalf("unexpected output:\n%s", out)
+		}
+
+		if err := Validate(&c); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := Validate(&cfg{}); !errors.Is(err, ErrMissingRequired) {
+			t.Fatalf("expected errors.Is(err, ErrMissingRequired), got %v", err)
+		}
+	})
+}
+
+// slowSource is a ContextSource that blocks on keys in slow until the
+// context is done.
+type slowSource struct {
+	MapSource
+	slow map[string]bool
+}
+
+func (s slowSource) LookupContext(ctx context.Context, key string) (string, bool, error) {
+	if s.slow[key] {
+		<-ctx.Done()
+		return "", false, ctx.Err()
+	}
+
+	value, ok := s.MapSource.Lookup(key)
+	return value, ok, nil
+}
+
+func TestLoadContext(t *testing.T) {
+	type cfg struct {
+		Host  string `env:"HOST"`
+		Token string `env:"TOKEN;optional"`
+	}
+
+	t.Run("context sources get the load context", func(t *testing.T) {
+		source := slowSource{MapSource: MapSource{"HOST": "db.local"}, slow: map[string]bool{"TOKEN": true}}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		var c cfg
+		err := LoadContext(ctx, &c, WithSource(source))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline error, got %v", err)
+		}
+		if want := `failed to look up field "Token" from ENV["TOKEN"]`; !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %v", want, err)
+		}
+		if c.Host != "db.local" {
+			t.Fatalf("expected fields before the slow one to load, got %+v", c)
+		}
+	})
+
+	t.Run("a canceled context stops loading", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		source := slowSource{MapSource: MapSource{"HOST": "db.local"}, slow: map[string]bool{"HOST": true}}
+		err := LoadContext(ctx, &cfg{}, WithSource(source))
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected canceled error, got %v", err)
+		}
+	})
+
+	t.Run("context sources work through LoadFile and expansion", func(t *testing.T) {
+		source := slowSource{MapSource: MapSource{"HOST": "${DOMAIN}"}, slow: map[string]bool{}}
+
+		var c cfg
+		err := LoadFile(&c, writeEnvFile(t, "DOMAIN=example.com"), WithSource(source), WithExpandVars())
+		if err != nil || c.Host != "example.com" {
+			t.Fatalf("expected expanded host, got %+v (%v)", c, err)
+		}
+	})
+
+	t.Run("other sources ignore the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var c cfg
+		err := LoadContext(ctx, &c, WithSource(MapSource{"HOST": "db.local"}))
+		if err != nil || c.Host != "db.local" {
+			t.Fatalf("expected config to load, got %+v (%v)", c, err)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package simpleenv
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WithExpectedPrefix makes Load fail when the Source holds keys starting with
+// prefix that no field reads, which usually means a typo in a deployment
+// template (APP_PORTT instead of APP_PORT). It can be given more than once,
+// and requires a Source that implements KeySource.
+func WithExpectedPrefix(prefix string) Option {
+	return func(o *options) {
+		o.expectedPrefixes = append(o.expectedPrefixes, prefix)
+	}
+}
+
+// markKnown records that a field reads key, so checkUnknownKeys does not
+// report it. Keys are only tracked when an expected prefix is set.
+func (l *loader) markKnown(key string) {
+	if len(l.expectedPrefixes) == 0 {
+		return
+	}
+	if l.knownKeys == nil {
+		l.knownKeys = map[string]bool{}
+	}
+
+	l.knownKeys[key] = true
+}
+
+// checkUnknownKeys returns an ErrUnknownKey error listing every key with an
+// expected prefix that no field read.
+func (l *loader) checkUnknownKeys() error {
+	if len(l.expectedPrefixes) == 0 {
+		return nil
+	}
+
+	keySource, ok := l.source.(KeySource)
+	if !ok {
+		return errors.New("WithExpectedPrefix requires a Source that implements KeySource")
+	}
+
+	var unknown []string
+	for _, key := range keySource.Keys() {
+		if l.knownKeys[key] {
+			continue
+		}
+		if slices.ContainsFunc(l.expectedPrefixes, func(prefix string) bool { return strings.HasPrefix(key, prefix) }) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	slices.Sort(unknown)
+	unknown = slices.Compact(unknown)
+	return newKindError(ErrUnknownKey, "unrecognized env vars with prefix %s: %s", quoteList(l.expectedPrefixes), strings.Join(unknown, ", "))
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+
+	return strings.Join(quoted, ", ")
+}
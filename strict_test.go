@@ -0,0 +1,89 @@
+package simpleenv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadExpectedPrefix(t *testing.T) {
+	type db struct {
+		Host string `env:"HOST"`
+	}
+
+	type cfg struct {
+		Port     int               `env:"APP_PORT;alias=APP_HTTP_PORT"`
+		Password string            `env:"APP_PASSWORD;optional"`
+		URL      string            `env:"APP_URL"`
+		Labels   map[string]string `env:"APP_LABEL_;collect;optional"`
+		DB       db                `env:";prefix=APP_DB_"`
+	}
+
+	base := func() MapSource {
+		return MapSource{
+			"APP_PORT":    "8080",
+			"APP_URL":     "http://${APP_HOSTNAME}",
+			"APP_DB_HOST": "db.local",
+			"OTHER_DEBUG": "true",
+		}
+	}
+
+	tests := []struct {
+		name     string
+		extra    MapSource
+		opts     []Option
+		contains string
+	}{
+		{name: "only known keys"},
+		{name: "alias keys are known", extra: MapSource{"APP_HTTP_PORT": "9090"}},
+		{name: "collected keys are known", extra: MapSource{"APP_LABEL_TEAM": "payments"}},
+		{name: "expanded keys are known", extra: MapSource{"APP_HOSTNAME": "localhost"}, opts: []Option{WithExpandVars()}},
+		{name: "secret file keys are known", extra: MapSource{"APP_PASSWORD_FILE": "/missing"}, opts: []Option{WithSecretFiles()}},
+		{name: "secret file keys are unknown without the option", extra: MapSource{"APP_PASSWORD_FILE": "/missing"}, contains: "APP_PASSWORD_FILE"},
+		{name: "typos are reported sorted", extra: MapSource{"APP_PORTT": "1", "APP_DB_HSOT": "x"}, contains: `unrecognized env vars with prefix "APP_": APP_DB_HSOT, APP_PORTT`},
+		{name: "every prefix is checked", extra: MapSource{"OTHER_DEBGU": "1"}, opts: []Option{WithExpectedPrefix("OTHER_")}, contains: `prefix "APP_", "OTHER_": OTHER_DEBGU, OTHER_DEBUG`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := base()
+			for key, value := range tt.extra {
+				source[key] = value
+			}
+
+			opts := append([]Option{WithSource(source), WithExpectedPrefix("APP_")}, tt.opts...)
+			err := LoadWithOptions(&cfg{}, opts...)
+			if tt.contains == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrUnknownKey) || !strings.Contains(err.Error(), tt.contains) {
+				t.Fatalf("expected ErrUnknownKey containing %q, got %v", tt.contains, err)
+			}
+		})
+	}
+
+	t.Run("schema reports unknown keys", func(t *testing.T) {
+		source := base()
+		source["APP_PORTT"] = "1"
+
+		schema, err := Compile[cfg](WithSource(source), WithExpectedPrefix("APP_"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := schema.Load(&cfg{}); !errors.Is(err, ErrUnknownKey) {
+			t.Fatalf("expected ErrUnknownKey, got %v", err)
+		}
+	})
+
+	t.Run("requires a KeySource", func(t *testing.T) {
+		source := &recordingSource{values: base()}
+		err := LoadWithOptions(&cfg{}, WithSource(source), WithExpectedPrefix("APP_"))
+		if err == nil || !strings.Contains(err.Error(), "KeySource") {
+			t.Fatalf("expected KeySource error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package simpleenv
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// transforms holds the functions registered with RegisterTransform, keyed
+// by name.
+var transforms sync.Map
+
+// RegisterTransform makes fn available to the transform= tag option under
+// name, to normalize values before they are validated and parsed:
+//
+//	simpleenv.RegisterTransform("lower", strings.ToLower)
+//	simpleenv.RegisterTransform("trimslash", func(value string) string {
+//		return strings.TrimRight(value, "/")
+//	})
+//
+//	type AppEnv struct {
+//		Environment string `env:"ENVIRONMENT;transform=lower;oneof=dev,prod"`
+//		BaseURL     string `env:"BASE_URL;transform=trimslash,lower"`
+//	}
+//
+// Register transforms before loading, typically from an init function.
+// RegisterTransform panics if name is empty or contains a comma, if fn is
+// nil, or if name is already registered.
+func RegisterTransform(name string, fn func(value string) string) {
+	if name == "" || strings.Contains(name, ",") {
+		panic(fmt.Sprintf("simpleenv: invalid transform name %q", name))
+	}
+	if fn == nil {
+		panic(fmt.Sprintf("simpleenv: RegisterTransform %q with nil func", name))
+	}
+	if _, loaded := transforms.LoadOrStore(name, fn); loaded {
+		panic(fmt.Sprintf("simpleenv: RegisterTransform called twice for %q", name))
+	}
+}
+
+// parseTransforms resolves the names of a transform= option, in order.
+func parseTransforms(option string) ([]func(string) string, error) {
+	var fns []func(string) string
+	for _, name := range strings.Split(strings.TrimPrefix(option, "transform="), ",") {
+		name = strings.TrimSpace(name)
+		fn, ok := transforms.Load(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown transform %q (register it with RegisterTransform)", name)
+		}
+
+		fns = append(fns, fn.(func(string) string))
+	}
+
+	return fns, nil
+}
+
+func applyTransforms(fns []func(string) string, value string) string {
+	for _, fn := range fns {
+		value = fn(value)
+	}
+
+	return value
+}
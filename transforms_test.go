@@ -0,0 +1,86 @@
+package simpleenv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func init() {
+	RegisterTransform("simpleenv-test-lower", strings.ToLower)
+	RegisterTransform("simpleenv-test-trimslash", func(value string) string {
+		return strings.TrimRight(value, "/")
+	})
+	RegisterTransform("simpleenv-test-suffix", func(value string) string {
+		return value + "/"
+	})
+}
+
+func TestLoadTransforms(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType reflect.Type
+		tag       string
+		value     *string
+		wantValue any
+		contains  string
+	}{
+		{name: "transform runs before constraints", fieldType: reflect.TypeOf(""), tag: "SIMPLEENV_TEST_TRANSFORM_ENV;transform=simpleenv-test-lower;oneof=dev,prod", value: strPtr("PROD"), wantValue: "prod"},
+		{name: "transforms chain in tag order", fieldType: reflect.TypeOf(""), tag: "SIMPLEENV_TEST_TRANSFORM_URL;transform=simpleenv-test-trimslash,simpleenv-test-suffix", value: strPtr("http://a//"), wantValue: "http://a/"},
+		{name: "transform runs after trimspace", fieldType: reflect.TypeOf(""), tag: "SIMPLEENV_TEST_TRANSFORM_TRIM;trimspace;transform=simpleenv-test-trimslash", value: strPtr(" /api/ "), wantValue: "/api"},
+		{name: "transform runs on defaults", fieldType: reflect.TypeOf(""), tag: "SIMPLEENV_TEST_TRANSFORM_DEFAULT;default=DEV;transform=simpleenv-test-lower", value: nil, wantValue: "dev"},
+		{name: "transform runs before type conversion", fieldType: reflect.TypeOf(false), tag: "SIMPLEENV_TEST_TRANSFORM_BOOL;transform=simpleenv-test-trimslash", value: strPtr("true/"), wantValue: true},
+		{name: "constraints see the transformed value", fieldType: reflect.TypeOf(""), tag: "SIMPLEENV_TEST_TRANSFORM_SLASH;transform=simpleenv-test-trimslash;notempty", value: strPtr("///"), contains: "a non-empty value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := loadSingleField(t, tt.fieldType, tt.tag, tt.value)
+			if tt.contains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.contains) {
+					t.Fatalf("expected error containing %q, got %v", tt.contains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(value.Interface(), tt.wantValue) {
+				t.Fatalf("unexpected value: got %#v, want %#v", value.Interface(), tt.wantValue)
+			}
+		})
+	}
+
+	t.Run("unknown transform is a tag error", func(t *testing.T) {
+		_, err := loadSingleField(t, reflect.TypeOf(""), "SIMPLEENV_TEST_TRANSFORM_UNKNOWN;optional;transform=simpleenv-test-missing", nil)
+		if !errors.Is(err, ErrInvalidTag) || !strings.Contains(err.Error(), `unknown transform "simpleenv-test-missing"`) {
+			t.Fatalf("expected unknown transform tag error, got %v", err)
+		}
+	})
+}
+
+func TestRegisterTransformPanics(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform string
+		fn        func(string) string
+	}{
+		{name: "empty name", transform: "", fn: strings.ToUpper},
+		{name: "name with comma", transform: "a,b", fn: strings.ToUpper},
+		{name: "nil func", transform: "simpleenv-test-nil", fn: nil},
+		{name: "duplicate name", transform: "simpleenv-test-lower", fn: strings.ToUpper},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected RegisterTransform to panic")
+				}
+			}()
+
+			RegisterTransform(tt.transform, tt.fn)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package simpleenv
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// Validate checks the constraints in the struct tags of an already populated
+// struct against its current field values, without reading any env vars.
+//
+//	cfg := AppEnv{Port: 8080, Environment: "production"}
+//	err := simpleenv.Validate(&cfg)
+//
+// Each field value is formatted the way it would be written in the env (for
+// example, durations as "1m30s" and []string joined by its separator) and then
+// checked like a loaded value. Nil pointers are treated as unset, and zero
+// values of optional fields are skipped, so a struct filled by Load always
+// passes Validate. Validate does not call a Validator hook on envConfig, so a
+// hook can call Validate itself. Options such as WithTagName and
+// WithTagSeparator select how tags are read, as for Load.
+func Validate(envConfig any, opts ...Option) error {
+	e, err := structElem(envConfig)
+	if err != nil {
+		return err
+	}
+
+	return newLoader(opts).validateStruct(e, "", "")
+}
+
+func (l *loader) validateStruct(structValue reflect.Value, fieldPath, keyPrefix string) error {
+	t := structValue.Type()
+
+	for i := range t.NumField() {
+		fieldType := t.Field(i)
+		if !isFieldVisible(fieldType) {
+			continue
+		}
+		fieldType.Name = fieldPath + fieldType.Name
+
+		err := l.validateField(structValue, fieldType, structValue.Field(i), keyPrefix)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *loader) validateField(structValue reflect.Value, fieldType reflect.StructField, fieldValue reflect.Value, keyPrefix string) error {
+	fieldTag, err := parseEnvTag(fieldType, l.tagName, l.tagSeparator)
+	if err != nil {
+		return err
+	}
+	if fieldTag.nested {
+		return l.validateStruct(nestedStruct(fieldValue, false), nestedFieldPath(fieldType), keyPrefix+fieldTag.prefix)
+	}
+	if !fieldTag.hasTag {
+		return nil
+	}
+
+	fieldTag.addKeyPrefix(keyPrefix)
+
+	if fieldTag.collect {
+		if fieldValue.Len() == 0 && !fieldTag.optional {
+			return fieldMissingError(fieldType.Name, fieldTag.key+"*")
+		}
+
+		return nil
+	}
+
+	if fieldTag.eqField != "" {
+		targetIndex, err := lookupFieldRef(structValue.Type(), fieldType, fieldTag)
+		if err != nil {
+			return err
+		}
+
+		// A target promoted through a nil embedded pointer is unset.
+		if target, err := structValue.FieldByIndexErr(targetIndex); err == nil {
+			err = checkFieldMatch(fieldMatch{fieldType: fieldType, fieldValue: fieldValue, fieldTag: fieldTag, target: target})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if kind := fieldValue.Kind(); kind == reflect.Pointer || kind == reflect.Interface {
+		if fieldValue.IsNil() {
+			if fieldTag.optional {
+				return nil
+			}
+
+			return fieldMissingError(fieldType.Name, fieldTag.key)
+		}
+
+		if kind == reflect.Pointer {
+			fieldValue = fieldValue.Elem()
+		}
+	} else if fieldTag.optional && fieldValue.IsZero() {
+		return nil
+	}
+
+	value, err := formatFieldValue(fieldType.Name, fieldValue, fieldTag)
+	if err != nil {
+		return err
+	}
+
+	if value == "" && !fieldTag.allowEmpty {
+		return fieldConstraintError(fieldType.Name, fieldTag.key, value, "", "a non-empty value")
+	}
+
+	return redactFieldError(validateConstraints(fieldType, fieldTag, value), fieldTag)
+}
+
+// formatFieldValue renders fieldValue as the env value that would load it,
+// so it can be checked by validateConstraints.
+func formatFieldValue(fieldName string, fieldValue reflect.Value, fieldTag envTag) (string, error) {
+	valueType := fieldValue.Type()
+	if fieldTag.json {
+		data, err := json.Marshal(fieldValue.Interface())
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal field %q (ENV[%q]): %w", fieldName, fieldTag.key, err)
+		}
+
+		return string(data), nil
+	}
+
+	if valueType.Kind() == reflect.Interface {
+		return formatKind(fieldName, fieldValue, fieldTag.key)
+	}
+
+	if fieldTag.char {
+		if valueType.Kind() == reflect.Uint8 {
+			return string(rune(fieldValue.Uint())), nil
+		}
+
+		return string(rune(fieldValue.Int())), nil
+	}
+
+	if valueType == timeDurationType {
+		return time.Duration(fieldValue.Int()).String(), nil
+	}
+
+	if valueType == timeType {
+		return fieldValue.Interface().(time.Time).Format(fieldTag.layout), nil
+	}
+
+	if valueType == urlType {
+		u := fieldValue.Interface().(url.URL)
+		return u.String(), nil
+	}
+
+	if !valueType.Implements(textMarshalerType) && fieldValue.CanAddr() && reflect.PointerTo(valueType).Implements(textMarshalerType) {
+		fieldValue = fieldValue.Addr()
+	}
+
+	if marshaler, ok := fieldValue.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal field %q (ENV[%q]): %w", fieldName, fieldTag.key, err)
+		}
+
+		return string(text), nil
+	}
+
+	switch valueType.Kind() {
+	case reflect.String:
+		return fieldValue.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(fieldValue.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fieldValue.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(fieldValue.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(fieldValue.Float(), 'g', -1, valueType.Bits()), nil
+	case reflect.Complex64, reflect.Complex128:
+		formatted := strconv.FormatComplex(fieldValue.Complex(), 'g', -1, valueType.Bits())
+		return strings.TrimSuffix(strings.TrimPrefix(formatted, "("), ")"), nil
+	case reflect.Slice:
+		if isByteSlice(valueType) {
+			if fieldTag.base64 {
+				return base64.StdEncoding.EncodeToString(fieldValue.Bytes()), nil
+			}
+
+			return string(fieldValue.Bytes()), nil
+		}
+		fallthrough
+	case reflect.Array:
+		parts := make([]string, fieldValue.Len())
+		for i := range parts {
+			elem := fieldValue.Index(i)
+			if elem.Kind() == reflect.Pointer {
+				if elem.IsNil() {
+					return "", fmt.Errorf("failed to marshal field %q (ENV[%q]): element %d is nil", fieldName, fieldTag.key, i)
+				}
+
+				elem = elem.Elem()
+			}
+
+			part, err := formatFieldValue(fieldName, elem, fieldTag.elementTag())
+			if err != nil {
+				return "", err
+			}
+
+			parts[i] = part
+		}
+
+		return strings.Join(parts, fieldTag.separator), nil
+	default:
+		return "", unsupportedTypeError(fieldName, fieldTag.key, valueType)
+	}
+}
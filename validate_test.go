@@ -0,0 +1,210 @@
+package simpleenv
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	type dbConfig struct {
+		Host string `env:"HOST;format=HOSTPORT"`
+	}
+
+	type cfg struct {
+		Environment string        `env:"SIMPLEENV_TEST_VALIDATE_ENVIRONMENT;oneof=development,production"`
+		Port        int           `env:"SIMPLEENV_TEST_VALIDATE_PORT;min=1;max=65535"`
+		Ratio       float64       `env:"SIMPLEENV_TEST_VALIDATE_RATIO;optional;max=1"`
+		Timeout     time.Duration `env:"SIMPLEENV_TEST_VALIDATE_TIMEOUT;min=1s"`
+		Hosts       []string      `env:"SIMPLEENV_TEST_VALIDATE_HOSTS;sep=|;regex=^[a-z.|]+$;maxlen=3"`
+		Token       *string       `env:"SIMPLEENV_TEST_VALIDATE_TOKEN;optional;minlen=8"`
+		Addr        net.IP        `env:"SIMPLEENV_TEST_VALIDATE_ADDR;optional;format=IPV4"`
+		DB          dbConfig      `env:";prefix=SIMPLEENV_TEST_VALIDATE_DB_"`
+	}
+
+	valid := func() cfg {
+		return cfg{
+			Environment: "production",
+			Port:        8080,
+			Timeout:     5 * time.Second,
+			Hosts:       []string{"a.example", "b.example"},
+			DB:          dbConfig{Host: "localhost:5432"},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		mutate      func(*cfg)
+		wantErr     error
+		errContains []string
+	}{
+		{name: "valid config", mutate: func(*cfg) {}},
+		{
+			name:   "optional zero values are skipped",
+			mutate: func(c *cfg) { c.Ratio = 0; c.Token = nil; c.Addr = nil },
+		},
+		{
+			name:   "text marshaler value",
+			mutate: func(c *cfg) { c.Addr = net.ParseIP("10.0.0.1") },
+		},
+		{
+			name:        "oneof",
+			mutate:      func(c *cfg) { c.Environment = "staging" },
+			wantErr:     ErrConstraint,
+			errContains: []string{`field "Environment"`, `ENV["SIMPLEENV_TEST_VALIDATE_ENVIRONMENT"]`, `got "staging"`},
+		},
+		{
+			name:        "integer bound",
+			mutate:      func(c *cfg) { c.Port = 0 },
+			wantErr:     ErrConstraint,
+			errContains: []string{`field "Port"`, "a value >= 1"},
+		},
+		{
+			name:        "float bound",
+			mutate:      func(c *cfg) { c.Ratio = 1.5 },
+			wantErr:     ErrConstraint,
+			errContains: []string{`got "1.5"`, "a value <= 1"},
+		},
+		{
+			name:        "duration bound",
+			mutate:      func(c *cfg) { c.Timeout = 500 * time.Millisecond },
+			wantErr:     ErrConstraint,
+			errContains: []string{`got "500ms"`},
+		},
+		{
+			name:        "slice joined with separator",
+			mutate:      func(c *cfg) { c.Hosts = []string{"a.example", "B.example"} },
+			wantErr:     ErrConstraint,
+			errContains: []string{`got "a.example|B.example"`},
+		},
+		{
+			name:        "empty slice",
+			mutate:      func(c *cfg) { c.Hosts = nil },
+			wantErr:     ErrConstraint,
+			errContains: []string{`field "Hosts"`, "a non-empty value"},
+		},
+		{
+			name:        "slice element count",
+			mutate:      func(c *cfg) { c.Hosts = []string{"a.example", "b.example", "c.example", "d.example"} },
+			wantErr:     ErrConstraint,
+			errContains: []string{`field "Hosts"`, "a list with <= 3 elements (got 4)"},
+		},
+		{
+			name:        "pointer value",
+			mutate:      func(c *cfg) { c.Token = strPtr("short") },
+			wantErr:     ErrConstraint,
+			errContains: []string{`field "Token"`, "a value with length >= 8"},
+		},
+		{
+			name:        "format",
+			mutate:      func(c *cfg) { c.Addr = net.ParseIP("::1") },
+			wantErr:     ErrConstraint,
+			errContains: []string{`got "::1"`, "IPv4"},
+		},
+		{
+			name:        "nested struct",
+			mutate:      func(c *cfg) { c.DB.Host = "localhost" },
+			wantErr:     ErrConstraint,
+			errContains: []string{`field "DB.Host"`, `ENV["SIMPLEENV_TEST_VALIDATE_DB_HOST"]`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.mutate(&c)
+
+			err := Validate(&c)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected errors.Is(err, %v), got %v", tt.wantErr, err)
+			}
+			for _, want := range tt.errContains {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("expected error to contain %q, got %q", want, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestValidateNilRequiredPointer(t *testing.T) {
+	type cfg struct {
+		Port *int `env:"SIMPLEENV_TEST_VALIDATE_NIL_PORT"`
+	}
+
+	err := Validate(&cfg{})
+	if !errors.Is(err, ErrMissingRequired) {
+		t.Fatalf("expected errors.Is(err, ErrMissingRequired), got %v", err)
+	}
+}
+
+func TestValidateDoesNotReadEnv(t *testing.T) {
+	type cfg struct {
+		Name string `env:"SIMPLEENV_TEST_VALIDATE_NAME;minlen=3"`
+	}
+
+	t.Setenv("SIMPLEENV_TEST_VALIDATE_NAME", "x")
+
+	c := cfg{Name: "service"}
+	if err := Validate(&c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if c.Name != "service" {
+		t.Fatalf("expected Validate to leave the struct unchanged, got %q", c.Name)
+	}
+}
+
+func TestValidateAfterLoad(t *testing.T) {
+	type cfg struct {
+		Port    int       `env:"SIMPLEENV_TEST_VALIDATE_LOADED_PORT;min=1"`
+		Retries int       `env:"SIMPLEENV_TEST_VALIDATE_LOADED_RETRIES;optional;min=1"`
+		Started time.Time `env:"SIMPLEENV_TEST_VALIDATE_LOADED_STARTED;layout=2006-01-02"`
+	}
+
+	t.Setenv("SIMPLEENV_TEST_VALIDATE_LOADED_PORT", "8080")
+	unsetEnv(t, "SIMPLEENV_TEST_VALIDATE_LOADED_RETRIES")
+	t.Setenv("SIMPLEENV_TEST_VALIDATE_LOADED_STARTED", "2024-01-02")
+
+	var c cfg
+	if err := Load(&c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := Validate(&c); err != nil {
+		t.Fatalf("expected loaded config to validate, got %v", err)
+	}
+}
+
+func TestValidateInput(t *testing.T) {
+	err := Validate(struct{}{})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected errors.Is(err, ErrInvalidInput), got %v", err)
+	}
+}
+
+func TestValidateTagOptions(t *testing.T) {
+	type commaCfg struct {
+		Port int `env:"PORT,min=10"`
+	}
+	type cfgTag struct {
+		Port int `cfg:"PORT;min=10"`
+	}
+
+	if err := Validate(&commaCfg{Port: 1}, WithTagSeparator(',')); !errors.Is(err, ErrConstraint) {
+		t.Fatalf("expected errors.Is(err, ErrConstraint) with a comma separator, got %v", err)
+	}
+	if err := Validate(&cfgTag{Port: 1}, WithTagName("cfg")); !errors.Is(err, ErrConstraint) {
+		t.Fatalf("expected errors.Is(err, ErrConstraint) with a custom tag name, got %v", err)
+	}
+	if err := Validate(&commaCfg{Port: 10}, WithTagSeparator(',')); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package simpleenv
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// validators holds the functions registered with RegisterValidator, keyed
+// by name.
+var validators sync.Map
+
+// RegisterValidator makes fn available to the validate= tag constraint under
+// name, for checks tags can't express:
+//
+//	simpleenv.RegisterValidator("port-free", func(value string) error {
+//		ln, err := net.Listen("tcp", ":"+value)
+//		if err != nil {
+//			return err
+//		}
+//		return ln.Close()
+//	})
+//
+//	type AppEnv struct {
+//		Port int `env:"PORT;validate=port-free"`
+//	}
+//
+// fn receives the raw value after expansion and trimming, and a non-nil
+// error fails the field with ErrConstraint. Register validators before
+// loading, typically from an init function. RegisterValidator panics if name
+// is empty or contains a comma, if fn is nil, or if name is already
+// registered.
+func RegisterValidator(name string, fn func(value string) error) {
+	if name == "" || strings.Contains(name, ",") {
+		panic(fmt.Sprintf("simpleenv: invalid validator name %q", name))
+	}
+	if fn == nil {
+		panic(fmt.Sprintf("simpleenv: RegisterValidator %q with nil func", name))
+	}
+	if _, loaded := validators.LoadOrStore(name, fn); loaded {
+		panic(fmt.Sprintf("simpleenv: RegisterValidator called twice for %q", name))
+	}
+}
+
+func lookupValidator(name string) (func(value string) error, bool) {
+	fn, ok := validators.Load(name)
+	if !ok {
+		return nil, false
+	}
+
+	return fn.(func(value string) error), true
+}
+
+// validatorNames splits the names of a validate= constraint.
+func validatorNames(constraint string) []string {
+	var names []string
+	for _, name := range strings.Split(strings.TrimPrefix(constraint, "validate="), ",") {
+		names = append(names, strings.TrimSpace(name))
+	}
+
+	return names
+}
@@ -0,0 +1,106 @@
+package simpleenv
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var errOdd = errors.New("value is odd")
+
+func init() {
+	RegisterValidator("simpleenv-test-even", func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		if n%2 != 0 {
+			return errOdd
+		}
+
+		return nil
+	})
+	RegisterValidator("simpleenv-test-lower", func(value string) error {
+		if value != strings.ToLower(value) {
+			return errors.New("value is not lowercase")
+		}
+
+		return nil
+	})
+}
+
+func TestLoadCustomValidators(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      string
+		value    string
+		contains string
+		wantErr  error
+	}{
+		{name: "accepted value", tag: "SIMPLEENV_TEST_VALIDATOR_EVEN;validate=simpleenv-test-even", value: "4"},
+		{name: "rejected value wraps the validator error", tag: "SIMPLEENV_TEST_VALIDATOR_EVEN;validate=simpleenv-test-even", value: "3", contains: `expected a value accepted by validator "simpleenv-test-even": value is odd`, wantErr: errOdd},
+		{name: "every listed validator runs", tag: "SIMPLEENV_TEST_VALIDATOR_BOTH;validate=simpleenv-test-lower, simpleenv-test-even", value: "1", contains: `validator "simpleenv-test-even"`, wantErr: errOdd},
+		{name: "runs after built-in constraints", tag: "SIMPLEENV_TEST_VALIDATOR_MIN;min=10;validate=simpleenv-test-even", value: "3", contains: "expected a value >= 10", wantErr: ErrConstraint},
+		{name: "unknown validator is a tag error", tag: "SIMPLEENV_TEST_VALIDATOR_UNKNOWN;validate=simpleenv-test-missing", value: "4", contains: `unknown validator "simpleenv-test-missing"`, wantErr: ErrInvalidTag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadSingleField(t, reflect.TypeOf(0), tt.tag, strPtr(tt.value))
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), tt.contains) {
+				t.Fatalf("expected error wrapping %v containing %q, got %v", tt.wantErr, tt.contains, err)
+			}
+		})
+	}
+
+	t.Run("unknown validator is reported when unset", func(t *testing.T) {
+		_, err := loadSingleField(t, reflect.TypeOf(0), "SIMPLEENV_TEST_VALIDATOR_UNSET;optional;validate=simpleenv-test-missing", nil)
+		if !errors.Is(err, ErrInvalidTag) {
+			t.Fatalf("expected errors.Is(err, ErrInvalidTag), got %v", err)
+		}
+	})
+
+	t.Run("Validate runs validators", func(t *testing.T) {
+		type cfg struct {
+			Workers int `env:"SIMPLEENV_TEST_VALIDATOR_WORKERS;validate=simpleenv-test-even"`
+		}
+
+		if err := Validate(&cfg{Workers: 3}); !errors.Is(err, errOdd) {
+			t.Fatalf("expected validator error, got %v", err)
+		}
+	})
+}
+
+func TestRegisterValidatorPanics(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator string
+		fn        func(string) error
+	}{
+		{name: "empty name", validator: "", fn: func(string) error { return nil }},
+		{name: "name with comma", validator: "a,b", fn: func(string) error { return nil }},
+		{name: "nil func", validator: "simpleenv-test-nil", fn: nil},
+		{name: "duplicate name", validator: "simpleenv-test-even", fn: func(string) error { return nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected RegisterValidator to panic")
+				}
+			}()
+
+			RegisterValidator(tt.validator, tt.fn)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package simpleenv
+
+import (
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const defaultPollInterval = time.Second
+
+// WithPollInterval sets how often WatchFile checks the file for changes
+// (defaults to one second).
+func WithPollInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = interval
+	}
+}
+
+// WatchFile loads envConfig with LoadFile and then keeps watching the file at
+// path, reloading whenever its modification time or size changes.
+//
+//	var current atomic.Pointer[Config]
+//	current.Store(&cfg)
+//	stop, err := simpleenv.WatchFile(&cfg, ".env", func(reloaded any, err error) {
+//		if err != nil {
+//			log.Printf("config reload failed: %v", err)
+//			return
+//		}
+//		current.Store(reloaded.(*Config))
+//	})
+//	defer stop()
+//
+// Only the initial load writes to envConfig. Each reload loads into a fresh
+// struct of the same type, and onReload is called from the watcher goroutine
+// with a pointer to it, or with a nil value and the error if the reload
+// failed. The caller decides how to publish the new config (an atomic.Pointer,
+// a mutex, a channel), so a broken edit never leaves a config half-updated and
+// readers never race with the watcher.
+//
+// The initial load runs before WatchFile returns, and its error is returned
+// directly. Call stop to end watching; it waits for an in-flight reload.
+func WatchFile(envConfig any, path string, onReload func(reloaded any, err error), opts ...Option) (stop func(), err error) {
+	err = LoadFile(envConfig, path, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := newLoader(opts).pollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	configType := reflect.TypeOf(envConfig).Elem()
+	last, _ := os.Stat(path)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			info, statErr := os.Stat(path)
+			if statErr == nil && last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+				continue
+			}
+			if statErr != nil && last == nil {
+				continue
+			}
+			last = info
+
+			var reloaded any = reflect.New(configType).Interface()
+			reloadErr := LoadFile(reloaded, path, opts...)
+			if reloadErr != nil {
+				reloaded = nil
+			}
+			if onReload != nil {
+				onReload(reloaded, reloadErr)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}, nil
+}
@@ -0,0 +1,174 @@
+package simpleenv
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatchFile(t *testing.T) {
+	type cfg struct {
+		Port int `env:"PORT;min=1"`
+	}
+
+	type reload struct {
+		cfg *cfg
+		err error
+	}
+
+	path := writeEnvFile(t, "PORT=8080\n")
+	reloads := make(chan reload, 10)
+
+	var c cfg
+	stop, err := WatchFile(&c, path, func(reloaded any, err error) {
+		fresh, _ := reloaded.(*cfg)
+		reloads <- reload{cfg: fresh, err: err}
+	}, WithSource(MapSource{}), WithPollInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer stop()
+
+	if c.Port != 8080 {
+		t.Fatalf("expected initial load, got %+v", c)
+	}
+
+	rewrite := func(content string, offset time.Duration) {
+		t.Helper()
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write env file: %v", err)
+		}
+
+		modTime := time.Now().Add(offset)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("failed to touch env file: %v", err)
+		}
+	}
+
+	waitReload := func() reload {
+		t.Helper()
+
+		select {
+		case r := <-reloads:
+			return r
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for reload")
+			return reload{}
+		}
+	}
+
+	rewrite("PORT=9090\n", time.Minute)
+	r := waitReload()
+	if r.err != nil {
+		t.Fatalf("expected reload to succeed, got %v", r.err)
+	}
+	if r.cfg == nil || r.cfg.Port != 9090 {
+		t.Fatalf("expected reloaded port 9090, got %+v", r.cfg)
+	}
+	if c.Port != 8080 {
+		t.Fatalf("expected reload to leave the initial config alone, got %d", c.Port)
+	}
+
+	rewrite("PORT=0\n", 2*time.Minute)
+	r = waitReload()
+	if r.err == nil {
+		t.Fatal("expected invalid reload to fail")
+	}
+	if r.cfg != nil {
+		t.Fatalf("expected no config for a failed reload, got %+v", r.cfg)
+	}
+
+	stop()
+	stop()
+	rewrite("PORT=7070\n", 3*time.Minute)
+	select {
+	case r := <-reloads:
+		t.Fatalf("expected no reload after stop, got %+v", r)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWatchFileConcurrentReads(t *testing.T) {
+	type cfg struct {
+		Port int `env:"PORT;min=1"`
+	}
+
+	path := writeEnvFile(t, "PORT=8080\n")
+	reloaded := make(chan struct{}, 10)
+
+	var c cfg
+	var current atomic.Pointer[cfg]
+	current.Store(&c)
+	stop, err := WatchFile(&c, path, func(fresh any, err error) {
+		if err == nil {
+			current.Store(fresh.(*cfg))
+		}
+		reloaded <- struct{}{}
+	}, WithSource(MapSource{}), WithPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer stop()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if port := current.Load().Port; port != 8080 && port != 9090 {
+				t.Errorf("unexpected port %d", port)
+				return
+			}
+			if c.Port != 8080 {
+				t.Errorf("expected the initial config to stay untouched, got %d", c.Port)
+				return
+			}
+		}
+	}()
+
+	if err := os.WriteFile(path, []byte("PORT=9090\n"), 0o600); err != nil {
+		t.Fatalf("failed to write env file: %v", err)
+	}
+	modTime := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("failed to touch env file: %v", err)
+	}
+
+	select {
+	case <-reloaded:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+	close(done)
+	wg.Wait()
+
+	if port := current.Load().Port; port != 9090 {
+		t.Fatalf("expected reloaded port 9090, got %d", port)
+	}
+}
+
+func TestWatchFileInitialError(t *testing.T) {
+	type cfg struct {
+		Port int `env:"PORT;min=1"`
+	}
+
+	var c cfg
+	stop, err := WatchFile(&c, writeEnvFile(t, "PORT=0\n"), nil, WithSource(MapSource{}))
+	if err == nil {
+		stop()
+		t.Fatal("expected initial load error, got nil")
+	}
+	if stop != nil {
+		t.Fatal("expected no stop func when the initial load fails")
+	}
+}